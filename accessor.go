@@ -0,0 +1,207 @@
+package iso8583parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Currency code field of the amount fields
+var amountCurrencyFields = map[int]int{
+	4:  49,
+	5:  50,
+	6:  51,
+	28: 49,
+	29: 50,
+	30: 49,
+	31: 50,
+	97: 50,
+}
+
+// Amount is an amount in minor units of its currency, e.g. 1500 with currency 360 or IDR
+type Amount struct {
+	Value    int64
+	Currency string
+}
+
+// Retrieves the amount of an amount field in minor units,
+// the currency is taken from the currency code field of the amount (e.g. field 49 for field 4) when present.
+// A signed amount (x+n) is negative when it is a debit.
+// An error may occur if the field is not exist or is not numeric
+func (iso *Iso8583Data) GetAmount(field int) (Amount, error) {
+	value, err := iso.GetInt(field)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	amount := Amount{Value: value}
+	if currencyField, ok := amountCurrencyFields[field]; ok {
+		amount.Currency, _ = iso.Elements.getElement(currencyField)
+	}
+
+	return amount, nil
+}
+
+// Define the amount of an amount field in minor units,
+// the currency code field of the amount is set too when the currency is not empty.
+// An error may occur if the amount does not fit the field or the field has no currency code field
+func (iso *Iso8583Data) SetAmount(field int, amount Amount) error {
+	if amount.Currency != "" {
+		currencyField, ok := amountCurrencyFields[field]
+		if !ok {
+			return newFieldError(field, PhaseValue, -1, fmt.Errorf("field %d has no currency code field", field))
+		}
+
+		if err := iso.SetField(currencyField, amount.Currency); err != nil {
+			return err
+		}
+	}
+
+	return iso.SetInt(field, amount.Value)
+}
+
+// Retrieves the value of a numeric field as integer,
+// a signed amount (x+n) is negative when it is a debit.
+// An error may occur if the field is not exist or is not numeric
+func (iso *Iso8583Data) GetInt(field int) (int64, error) {
+	value, err := iso.GetField(field)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := parseFieldInt(iso.Spec.Fields[field], value)
+	if err != nil {
+		return 0, newFieldError(field, PhaseValue, -1, err)
+	}
+
+	return n, nil
+}
+
+// Define the value of a numeric field from an integer, a fixed field is left padded with zero.
+// An error may occur if the number is negative for a field that is not a signed amount (x+n)
+// or the number does not fit the field
+func (iso *Iso8583Data) SetInt(field int, n int64) error {
+	value, err := formatFieldInt(iso.Spec.Fields[field], n)
+	if err != nil {
+		return newFieldError(field, PhaseValue, -1, err)
+	}
+
+	return iso.SetField(field, value)
+}
+
+// Retrieves the date and time of a date and time field, the layout is the Layout of the field spesification
+// (e.g. MMDDhhmmss for field 7 and YYMM for field 14 in the 1987 specification) or is chosen by the field length.
+// Parts that are not in the field are taken from ref, the year is the one that puts the date closest to ref
+// so a transmission date of 1231 received on January 1st is in the previous year
+// An error may occur if the field is not exist, the value does not match the layout
+// or the date does not exist in the chosen year (ErrInvalidDate, e.g. 0229 closest to a common year)
+func (iso *Iso8583Data) GetDateTime(field int, ref time.Time) (time.Time, error) {
+	value, err := iso.GetField(field)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	layout, err := timeLayout(iso.Spec.Fields[field], structTag{})
+	if err != nil {
+		return time.Time{}, newFieldError(field, PhaseValue, -1, err)
+	}
+
+	t, err := parseDateTime(layout, value, ref)
+	if err != nil {
+		return time.Time{}, newFieldError(field, PhaseValue, -1, err)
+	}
+
+	return t, nil
+}
+
+// Define the value of a date and time field, the layout is chosen like GetDateTime.
+// An error may occur if there is no layout for the field length
+func (iso *Iso8583Data) SetDateTime(field int, t time.Time) error {
+	layout, err := timeLayout(iso.Spec.Fields[field], structTag{})
+	if err != nil {
+		return newFieldError(field, PhaseValue, -1, err)
+	}
+
+	return iso.SetField(field, t.Format(layout))
+}
+
+// Parse a date and time field value and complete it with the parts of ref that are not in the layout
+func parseDateTime(layout, value string, ref time.Time) (time.Time, error) {
+	t, err := time.ParseInLocation(layout, value, ref.Location())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return completeDateTime(t, layout, ref)
+}
+
+// Complete a parsed date and time with the parts of ref that are not in the layout.
+// Without year the year closest to ref is chosen before the date is built, an error occurs when the date
+// does not exist in that year (e.g. February 29th)
+func completeDateTime(t time.Time, layout string, ref time.Time) (time.Time, error) {
+	hasYear := strings.Contains(layout, "06")
+	hasDate := strings.Contains(layout, "01")
+
+	if !hasDate {
+		return time.Date(ref.Year(), ref.Month(), ref.Day(), t.Hour(), t.Minute(), t.Second(), 0, ref.Location()), nil
+	}
+
+	if hasYear {
+		return t, nil
+	}
+
+	year := ref.Year()
+	closest := time.Duration(math.MaxInt64)
+	for _, candidate := range []int{ref.Year() - 1, ref.Year(), ref.Year() + 1} {
+		// Only the distance is taken from this date, February 29th may be moved to March 1st here
+		distance := time.Date(candidate, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, ref.Location()).Sub(ref)
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance < closest {
+			year, closest = candidate, distance
+		}
+	}
+
+	completed := time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, ref.Location())
+	if completed.Month() != t.Month() {
+		return time.Time{}, fmt.Errorf("%w: %s %d does not exist in %d", ErrInvalidDate, t.Month(), t.Day(), year)
+	}
+
+	return completed, nil
+}
+
+// Format an integer as numeric field value, a signed amount (x+n) is prefixed with C for credit or D for debit
+func formatFieldInt(spec FieldSpec, n int64) (string, error) {
+	if spec.ContentType == ContentTypeSignedAmount {
+		if n < 0 {
+			return "D" + strconv.FormatInt(-n, 10), nil
+		}
+		return "C" + strconv.FormatInt(n, 10), nil
+	}
+
+	if n < 0 {
+		return "", fmt.Errorf("negative value %d for content type %s", n, spec.ContentType)
+	}
+
+	return strconv.FormatInt(n, 10), nil
+}
+
+// Parse a numeric field value, a signed amount (x+n) is negative when it starts with D
+func parseFieldInt(spec FieldSpec, value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if spec.ContentType == ContentTypeSignedAmount && len(value) > 0 {
+		n, err := strconv.ParseInt(value[1:], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if value[0] == 'D' {
+			return -n, nil
+		}
+		return n, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
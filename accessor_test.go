@@ -0,0 +1,147 @@
+package iso8583parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmount(t *testing.T) {
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+
+	require.Nil(t, isoParser.SetAmount(4, Amount{Value: 1500, Currency: "360"}))
+	require.Nil(t, isoParser.SetAmount(28, Amount{Value: -250}))
+
+	bit4, _ := isoParser.GetField(4)
+	assert.Equal(t, "000000001500", bit4, "Expected Bit4 to be equal")
+	bit49, _ := isoParser.GetField(49)
+	assert.Equal(t, "360", bit49, "Expected Bit49 to be equal")
+	bit28, _ := isoParser.GetField(28)
+	assert.Equal(t, "D00000250", bit28, "Expected Bit28 to be equal")
+
+	amount, err := isoParser.GetAmount(4)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, Amount{Value: 1500, Currency: "360"}, amount, "Expected amount to be equal")
+
+	fee, err := isoParser.GetAmount(28)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, Amount{Value: -250, Currency: "360"}, fee, "Expected amount to be equal")
+
+	err = isoParser.SetAmount(4, Amount{Value: -1})
+	assert.NotNil(t, err, "Expected error negative amount")
+
+	err = isoParser.SetAmount(54, Amount{Value: 1, Currency: "360"})
+	assert.NotNil(t, err, "Expected error no currency field")
+
+	_, err = isoParser.GetAmount(5)
+	assert.NotNil(t, err, "Expected error field not exist")
+}
+
+func TestInt(t *testing.T) {
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+
+	require.Nil(t, isoParser.SetInt(11, 42))
+	bit11, _ := isoParser.GetField(11)
+	assert.Equal(t, "000042", bit11, "Expected Bit11 to be equal")
+
+	stan, err := isoParser.GetInt(11)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, int64(42), stan, "Expected STAN to be equal")
+
+	err = isoParser.SetInt(11, 1234567)
+	assert.ErrorIs(t, err, ErrFieldTooLong)
+
+	isoParser.SetField(37, "ABC")
+	_, err = isoParser.GetInt(37)
+	assert.NotNil(t, err, "Expected error not numeric")
+}
+
+func TestDateTime(t *testing.T) {
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+
+	ref := time.Date(2026, 1, 1, 0, 0, 30, 0, time.UTC)
+
+	t.Run("Previous year", func(t *testing.T) {
+		isoParser.SetField(7, "1231235959")
+		dt, err := isoParser.GetDateTime(7, ref)
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC), dt, "Expected date time to be equal")
+	})
+
+	t.Run("Same year", func(t *testing.T) {
+		isoParser.SetField(7, "0711170215")
+		dt, err := isoParser.GetDateTime(7, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, time.Date(2026, 7, 11, 17, 2, 15, 0, time.UTC), dt, "Expected date time to be equal")
+	})
+
+	t.Run("Time only", func(t *testing.T) {
+		require.Nil(t, isoParser.SetDateTime(12, time.Date(2026, 1, 1, 8, 30, 5, 0, time.UTC)))
+		bit12, _ := isoParser.GetField(12)
+		assert.Equal(t, "083005", bit12, "Expected Bit12 to be equal")
+
+		dt, err := isoParser.GetDateTime(12, ref)
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, time.Date(2026, 1, 1, 8, 30, 5, 0, time.UTC), dt, "Expected date time to be equal")
+	})
+
+	t.Run("Expiration date", func(t *testing.T) {
+		isoParser.SetField(14, "2812")
+		dt, err := isoParser.GetDateTime(14, ref)
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, time.Date(2028, 12, 1, 0, 0, 0, 0, time.UTC), dt, "Expected YYMM date to be equal")
+
+		require.Nil(t, isoParser.SetDateTime(14, time.Date(2030, 6, 15, 0, 0, 0, 0, time.UTC)))
+		bit14, _ := isoParser.GetField(14)
+		assert.Equal(t, "3006", bit14, "Expected Bit14 to be equal")
+	})
+
+	t.Run("Leap day", func(t *testing.T) {
+		isoParser.SetField(13, "0229")
+		dt, err := isoParser.GetDateTime(13, time.Date(2027, 12, 31, 0, 0, 0, 0, time.UTC))
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), dt, "Expected leap day of the next year")
+
+		_, err = isoParser.GetDateTime(13, time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC))
+		assert.ErrorIs(t, err, ErrInvalidDate, "Expected error leap day in a common year")
+	})
+
+	t.Run("Invalid date", func(t *testing.T) {
+		isoParser.SetField(7, "1399000000")
+		_, err := isoParser.GetDateTime(7, ref)
+		assert.NotNil(t, err, "Expected error invalid date")
+	})
+}
+
+func TestDateTime1993(t *testing.T) {
+	isoParser, err := NewFromSpec(SpecData1993)
+	require.Nil(t, err, "Error should be nil")
+
+	ref := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	require.Nil(t, isoParser.SetDateTime(15, time.Date(2026, 10, 17, 23, 59, 0, 0, time.UTC)))
+	bit15, _ := isoParser.GetField(15)
+	assert.Equal(t, "261017", bit15, "Expected settlement date YYMMDD")
+
+	dt, err := isoParser.GetDateTime(15, ref)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), dt, "Expected settlement date to be equal")
+
+	isoParser.SetField(13, "2601")
+	dt, err = isoParser.GetDateTime(13, ref)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), dt, "Expected effective date YYMM")
+
+	// A field without Layout falls back to the layout of its length
+	spec := SpecData{Fields: map[int]FieldSpec{15: {ContentType: "n", LenType: "fixed", MaxLen: 6}}}
+	noLayout, _ := NewFromSpec(spec)
+	noLayout.SetField(15, "083005")
+	dt, err = noLayout.GetDateTime(15, ref)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, time.Date(2026, 10, 16, 8, 30, 5, 0, time.UTC), dt, "Expected time of the fallback layout")
+}
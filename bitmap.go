@@ -0,0 +1,79 @@
+package iso8583parser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Supported bitmap encodings. An empty encoding in SpecData is treated as hex.
+const (
+	BitmapEncodingHex    = "hex"    // 16 ASCII hex characters per bitmap
+	BitmapEncodingBinary = "binary" // 8 raw bytes per bitmap
+	BitmapEncodingEBCDIC = "ebcdic" // 16 EBCDIC hex characters per bitmap
+)
+
+// Get the number of bytes used by one bitmap (64 bits) on the wire
+func bitmapBlockLength(encoding string) (int, error) {
+	switch strings.ToLower(encoding) {
+	case "", BitmapEncodingHex, BitmapEncodingEBCDIC:
+		return BitmapLength, nil
+	case BitmapEncodingBinary:
+		return BitmapLength / 2, nil
+	}
+
+	return 0, fmt.Errorf("%s is an invalid BitmapEncoding", encoding)
+}
+
+// Encode the bitmap bits into wire format according to the bitmap encoding
+func encodeBitmap(bits []int, encoding string) ([]byte, error) {
+	bitmapHex, err := BitsIntArrayToHex(bits)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(encoding) {
+	case "", BitmapEncodingHex:
+		return []byte(bitmapHex), nil
+	case BitmapEncodingBinary:
+		return hex.DecodeString(bitmapHex)
+	case BitmapEncodingEBCDIC:
+		return asciiToEbcdic(strings.ToUpper(bitmapHex))
+	}
+
+	return nil, fmt.Errorf("%s is an invalid BitmapEncoding", encoding)
+}
+
+// Decode one bitmap (64 bits) from the beginning of data into 8 raw bytes
+// Errors can occur if data is shorter than one bitmap or contains invalid hex characters
+func decodeBitmapBlock(data []byte, encoding string) (bitmap []byte, read int, err error) {
+	read, err = bitmapBlockLength(encoding)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(data) < read {
+		return nil, 0, ErrNotEnoughData
+	}
+
+	bitmap = make([]byte, 8)
+	switch strings.ToLower(encoding) {
+	case BitmapEncodingBinary:
+		copy(bitmap, data[:read])
+	case BitmapEncodingEBCDIC:
+		bitmapHex, err := ebcdicToAscii(data[:read])
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if _, err := hex.Decode(bitmap, []byte(bitmapHex)); err != nil {
+			return nil, 0, err
+		}
+	default:
+		if _, err := hex.Decode(bitmap, data[:read]); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return bitmap, read, nil
+}
@@ -0,0 +1,103 @@
+package iso8583parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeBitmap(t *testing.T) {
+	bits := make([]int, bitmapSizePrimary)
+	bits[2] = 1
+	bits[10] = 1
+
+	t.Run("Hex", func(t *testing.T) {
+		bitmapBytes, err := encodeBitmap(bits, "")
+		assert.Nil(t, err, "Error should be nil")
+		assert.Equal(t, []byte("2020000000000000"), bitmapBytes, "Expected bitmap to be equal")
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		bitmapBytes, err := encodeBitmap(bits, BitmapEncodingBinary)
+		assert.Nil(t, err, "Error should be nil")
+		assert.Equal(t, []byte{0x20, 0x20, 0, 0, 0, 0, 0, 0}, bitmapBytes, "Expected bitmap to be equal")
+	})
+
+	t.Run("EBCDIC", func(t *testing.T) {
+		bitmapBytes, err := encodeBitmap(bits, BitmapEncodingEBCDIC)
+		assert.Nil(t, err, "Error should be nil")
+		assert.Equal(t, []byte{0xf2, 0xf0, 0xf2, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0}, bitmapBytes, "Expected bitmap to be equal")
+	})
+
+	t.Run("Invalid encoding", func(t *testing.T) {
+		_, err := encodeBitmap(bits, "base64")
+		assert.NotNil(t, err, "Expected error bitmap encoding")
+	})
+}
+
+func TestDecodeBitmapBlock(t *testing.T) {
+	t.Run("Binary", func(t *testing.T) {
+		bitmap, read, err := decodeBitmapBlock([]byte{0x20, 0x20, 0, 0, 0, 0, 0, 0, 0x31}, BitmapEncodingBinary)
+		assert.Nil(t, err, "Error should be nil")
+		assert.Equal(t, 8, read, "Expected read bytes to be equal")
+		assert.Equal(t, []byte{0x20, 0x20, 0, 0, 0, 0, 0, 0}, bitmap, "Expected bitmap to be equal")
+	})
+
+	t.Run("Too short", func(t *testing.T) {
+		_, _, err := decodeBitmapBlock([]byte("20200000"), BitmapEncodingHex)
+		assert.ErrorIs(t, err, ErrNotEnoughData)
+	})
+}
+
+func TestMarshalBinaryBitmap(t *testing.T) {
+	specData := SpecData{
+		BitmapEncoding: BitmapEncodingBinary,
+		Fields: map[int]FieldSpec{
+			3:   {ContentType: "n", LenType: "fixed", MaxLen: 6},
+			100: {ContentType: "n", LenType: "llvar", MaxLen: 11},
+		},
+	}
+
+	isoParser, err := NewFromSpec(specData)
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.AddMTI("0200")
+	isoParser.SetField(3, "300000")
+	isoParser.SetField(100, "123456")
+
+	isoMsg, err := isoParser.Marshal()
+	require.Nil(t, err, "Error should be nil")
+
+	expected := []byte("0200")
+	expected = append(expected, 0xa0, 0, 0, 0, 0, 0, 0, 0)
+	expected = append(expected, 0, 0, 0, 0, 0x10, 0, 0, 0)
+	expected = append(expected, []byte("30000006123456")...)
+	require.Equal(t, expected, isoMsg, "Expected iso message to be equal")
+
+	unpacked, err := NewFromSpec(specData)
+	require.Nil(t, err, "Error should be nil")
+
+	err = unpacked.Unmarshal(isoMsg)
+	require.Nil(t, err, "Error should be nil")
+
+	bit100, _ := unpacked.GetField(100)
+	require.Equal(t, bitmapSizeSecondary, unpacked.BitmapSize, "Expected bitmap size to be equal")
+	require.Equal(t, "123456", bit100, "Expected Bit100 to be equal")
+
+	err = unpacked.Unmarshal(isoMsg[:14])
+	require.ErrorIs(t, err, ErrDataToShortSecondaryBitmap)
+}
+
+func TestSpecFromFileWithOptions(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "spec.yml")
+	content := "BitmapEncoding: binary\nFields:\n  3:\n    ContentType: \"n\"\n    LenType: fixed\n    MaxLen: 6\n"
+	require.Nil(t, os.WriteFile(filename, []byte(content), 0o600))
+
+	specData, err := SpecFromFile(filename)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, BitmapEncodingBinary, specData.BitmapEncoding, "Expected bitmap encoding to be equal")
+	assert.Equal(t, 6, specData.Fields[3].MaxLen, "Expected field spec to be loaded")
+}
@@ -0,0 +1,297 @@
+package iso8583parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultSendTimeout is the time Send waits for a response when the context has no deadline
+const DefaultSendTimeout = 30 * time.Second
+
+// Default fields that match a response to its request, the STAN and the terminal identification
+var DefaultMatchFields = []int{11, 41}
+
+// Sender sends a request message and waits for its response
+type Sender interface {
+	Send(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error)
+}
+
+// ClientOption configures the behaviour of a Client
+type ClientOption func(c *Client)
+
+// WithFramer sets the framing of the messages on the connection, FrameHeaderBinary2 is used by default
+func WithFramer(framer Framer) ClientOption {
+	return func(c *Client) {
+		c.framer = framer
+	}
+}
+
+// WithMatchFields sets the fields that match a response to its request, DefaultMatchFields is used by default.
+// A field that is absent in the request is left out of the match
+func WithMatchFields(fields ...int) ClientOption {
+	return func(c *Client) {
+		c.matchFields = fields
+	}
+}
+
+// WithSendTimeout sets the time Send waits for a response when the context has no deadline
+func WithSendTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithMessageOptions sets the options of the Iso8583Data objects the received messages are unmarshalled into
+func WithMessageOptions(opts ...Option) ClientOption {
+	return func(c *Client) {
+		c.messageOpts = opts
+	}
+}
+
+// WithUnmatchedHandler sets the function that receives the messages that do not match a waiting request,
+// like late responses after a timeout and requests sent by the host. The messages are dropped by default
+func WithUnmatchedHandler(handler func(msg *Iso8583Data)) ClientOption {
+	return func(c *Client) {
+		c.unmatched = handler
+	}
+}
+
+// WithErrorHandler sets the function that receives the errors of received messages that can not be unmarshalled,
+// the errors are dropped by default
+func WithErrorHandler(handler func(err error)) ClientOption {
+	return func(c *Client) {
+		c.onError = handler
+	}
+}
+
+// Client sends requests over one persistent connection and matches the responses to the requests,
+// so many goroutines can Send at the same time
+type Client struct {
+	conn        net.Conn
+	spec        SpecData
+	framer      Framer
+	matchFields []int
+	timeout     time.Duration
+	messageOpts []Option
+	unmatched   func(msg *Iso8583Data)
+	onError     func(err error)
+	network     *NetworkManagement
+
+	writeMu sync.Mutex
+	stan    atomic.Uint32
+
+	mu      sync.Mutex
+	pending map[string]chan *Iso8583Data
+	state   LinkState
+	err     error
+	done    chan struct{}
+}
+
+// Create a new Client that sends messages over conn, the received messages are unmarshalled with spec.
+// The client reads from conn until Close is called or conn fails
+func NewClient(conn net.Conn, spec SpecData, opts ...ClientOption) *Client {
+	c := &Client{
+		conn:        conn,
+		spec:        spec,
+		framer:      NewFramer(FrameHeaderBinary2),
+		matchFields: DefaultMatchFields,
+		timeout:     DefaultSendTimeout,
+		pending:     make(map[string]chan *Iso8583Data),
+		state:       LinkConnected,
+		done:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	go c.readLoop()
+	if c.network != nil {
+		go c.manageNetwork()
+	}
+	return c
+}
+
+// Send the request and wait for the matching response.
+// An error may occur if the request has none of the match fields, if a request with the same match fields is waiting,
+// if the response does not arrive before the context is done or the send timeout (ErrSendTimeout),
+// if the connection is closed and if the client must sign on first (ErrNotSignedOn)
+func (c *Client) Send(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+	key, err := c.matchKey(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.network != nil && c.network.SignOn && !request.Mti.IsNetworkManagement() && c.State() != LinkSignedOn {
+		return nil, ErrNotSignedOn
+	}
+
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	ch := make(chan *Iso8583Data, 1)
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	if _, exist := c.pending[key]; exist {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateRequest, key)
+	}
+	c.pending[key] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		// The delivered response already removed the channel, the key may belong to a newer request
+		c.mu.Lock()
+		if c.pending[key] == ch {
+			delete(c.pending, key)
+		}
+		c.mu.Unlock()
+	}()
+
+	if err := c.Write(request); err != nil {
+		return nil, err
+	}
+
+	select {
+	case response := <-ch:
+		return response, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %s", ErrSendTimeout, key)
+		}
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, c.Err()
+	}
+}
+
+// Write a message without waiting for a response, e.g. an advice or the response to a request of the host
+func (c *Client) Write(msg *Iso8583Data) error {
+	payload, err := msg.Marshal()
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if err := c.framer.WriteFrame(c.conn, payload); err != nil {
+		c.fail(err)
+		return err
+	}
+
+	return nil
+}
+
+// Close the connection, waiting requests fail with ErrClientClosed
+func (c *Client) Close() error {
+	c.fail(ErrClientClosed)
+	return c.conn.Close()
+}
+
+// Done is closed when the connection is closed or failed
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the reason the connection is done, nil while it is open
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Mark the client as done with the first error
+func (c *Client) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return
+	}
+
+	c.err = err
+	close(c.done)
+}
+
+// Read the messages from the connection and deliver every response to its waiting request
+func (c *Client) readLoop() {
+	for {
+		payload, err := c.framer.ReadFrame(c.conn)
+		if err != nil {
+			c.fail(fmt.Errorf("%w: %w", ErrClientClosed, err))
+			c.conn.Close()
+			return
+		}
+
+		msg, err := NewFromSpec(c.spec, c.messageOpts...)
+		if err == nil {
+			err = msg.Unmarshal(payload)
+		}
+		if err != nil {
+			c.reportError(err)
+			continue
+		}
+
+		c.deliver(msg)
+	}
+}
+
+// Deliver a received message to the request it responds to, or to the unmatched handler.
+// With network management the network management requests of the host are answered
+func (c *Client) deliver(msg *Iso8583Data) {
+	if c.network != nil && msg.Mti.Get() == MTINetworkRequest {
+		go c.answerNetwork(msg)
+		return
+	}
+
+	if msg.Mti.IsResponse() {
+		if key, err := c.matchKey(msg); err == nil {
+			c.mu.Lock()
+			ch, ok := c.pending[key]
+			delete(c.pending, key)
+			c.mu.Unlock()
+
+			if ok {
+				ch <- msg
+				return
+			}
+		}
+	}
+
+	if c.unmatched != nil {
+		c.unmatched(msg)
+	}
+}
+
+// Create the key that matches a response to its request from the values of the match fields
+func (c *Client) matchKey(msg *Iso8583Data) (string, error) {
+	values := make([]string, 0, len(c.matchFields))
+	for _, field := range c.matchFields {
+		value, exist := msg.Elements.getElement(field)
+		if !exist {
+			continue
+		}
+		values = append(values, fmt.Sprintf("%d=%s", field, value))
+	}
+
+	if len(values) == 0 {
+		return "", fmt.Errorf("%w: fields %v", ErrNoMatchFields, c.matchFields)
+	}
+
+	return strings.Join(values, ","), nil
+}
@@ -0,0 +1,194 @@
+package iso8583parser
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Read the requests of the client on conn and hand them to serve, until conn is closed
+func runTestHost(t *testing.T, conn net.Conn, serve func(request *Iso8583Data)) {
+	t.Helper()
+
+	framer := NewFramer(FrameHeaderBinary2)
+	go func() {
+		for {
+			request, _ := NewFromSpec(SpecData1987)
+			if err := framer.ReadMessage(conn, request); err != nil {
+				return
+			}
+			serve(request)
+		}
+	}()
+}
+
+// Create the response of a test request echoing the match fields
+func testResponse(request *Iso8583Data, responseCode string) *Iso8583Data {
+	response, _ := NewFromSpec(SpecData1987)
+
+	mti, _ := strconv.Atoi(request.Mti.Get())
+	response.AddMTI(fmt.Sprintf("%04d", mti+10))
+	for _, field := range []int{11, 41} {
+		if value, err := request.GetField(field); err == nil {
+			response.SetField(field, value)
+		}
+	}
+	response.SetField(39, responseCode)
+
+	return response
+}
+
+func testRequest(mti, stan string) *Iso8583Data {
+	request, _ := NewFromSpec(SpecData1987)
+	request.AddMTI(mti)
+	request.SetField(11, stan)
+	request.SetField(41, "TERM0001")
+	return request
+}
+
+func TestClientSend(t *testing.T) {
+	clientConn, hostConn := net.Pipe()
+	framer := NewFramer(FrameHeaderBinary2)
+
+	// Answer every two requests in reverse order
+	var mu sync.Mutex
+	var waiting []*Iso8583Data
+	runTestHost(t, hostConn, func(request *Iso8583Data) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		waiting = append(waiting, request)
+		if len(waiting) < 2 {
+			return
+		}
+		for i := len(waiting) - 1; i >= 0; i-- {
+			stan, _ := waiting[i].GetField(11)
+			go framer.WriteMessage(hostConn, testResponse(waiting[i], stan[4:]))
+		}
+		waiting = nil
+	})
+
+	client := NewClient(clientConn, SpecData1987)
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for _, stan := range []string{"000001", "000002"} {
+		wg.Add(1)
+		go func(stan string) {
+			defer wg.Done()
+
+			response, err := client.Send(context.Background(), testRequest("0200", stan))
+			require.Nil(t, err, "Error should be nil")
+			assert.Equal(t, "0210", response.Mti.Get(), "Expected MTI to be equal")
+
+			bit11, _ := response.GetField(11)
+			assert.Equal(t, stan, bit11, "Expected response to match the request")
+			bit39, _ := response.GetField(39)
+			assert.Equal(t, stan[4:], bit39, "Expected response to match the request")
+		}(stan)
+	}
+	wg.Wait()
+}
+
+func TestClientTimeout(t *testing.T) {
+	clientConn, hostConn := net.Pipe()
+	framer := NewFramer(FrameHeaderBinary2)
+
+	requests := make(chan *Iso8583Data, 1)
+	runTestHost(t, hostConn, func(request *Iso8583Data) {
+		requests <- request
+	})
+
+	late := make(chan *Iso8583Data, 1)
+	client := NewClient(clientConn, SpecData1987, WithSendTimeout(50*time.Millisecond), WithUnmatchedHandler(func(msg *Iso8583Data) {
+		late <- msg
+	}))
+	defer client.Close()
+
+	_, err := client.Send(context.Background(), testRequest("0200", "000003"))
+	assert.ErrorIs(t, err, ErrSendTimeout)
+
+	require.Nil(t, framer.WriteMessage(hostConn, testResponse(<-requests, "00")))
+
+	select {
+	case msg := <-late:
+		bit11, _ := msg.GetField(11)
+		assert.Equal(t, "000003", bit11, "Expected late response to be handled")
+	case <-time.After(time.Second):
+		t.Fatal("Expected late response to be handled")
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	clientConn, hostConn := net.Pipe()
+	runTestHost(t, hostConn, func(request *Iso8583Data) {})
+
+	client := NewClient(clientConn, SpecData1987)
+
+	t.Run("No match fields", func(t *testing.T) {
+		request, _ := NewFromSpec(SpecData1987)
+		request.AddMTI("0800")
+		_, err := client.Send(context.Background(), request)
+		assert.ErrorIs(t, err, ErrNoMatchFields)
+	})
+
+	t.Run("Duplicate request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go client.Send(ctx, testRequest("0200", "000004"))
+		require.Eventually(t, func() bool {
+			client.mu.Lock()
+			defer client.mu.Unlock()
+			return len(client.pending) == 1
+		}, time.Second, time.Millisecond)
+
+		_, err := client.Send(ctx, testRequest("0200", "000004"))
+		assert.ErrorIs(t, err, ErrDuplicateRequest)
+	})
+
+	t.Run("Same key after response", func(t *testing.T) {
+		request := testRequest("0200", "000006")
+		key, _ := client.matchKey(request)
+
+		result := make(chan *Iso8583Data)
+		go func() {
+			response, _ := client.Send(context.Background(), request)
+			result <- response
+		}()
+		require.Eventually(t, func() bool {
+			client.mu.Lock()
+			defer client.mu.Unlock()
+			return client.pending[key] != nil
+		}, time.Second, time.Millisecond)
+
+		// Deliver the response like the read loop and register the next request with the same key
+		next := make(chan *Iso8583Data, 1)
+		client.mu.Lock()
+		ch := client.pending[key]
+		client.pending[key] = next
+		client.mu.Unlock()
+		ch <- testResponse(request, "00")
+		require.NotNil(t, <-result, "Expected the response to be received")
+
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		assert.Equal(t, next, client.pending[key], "Expected the next request to stay registered")
+		delete(client.pending, key)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		require.Nil(t, client.Close())
+		<-client.Done()
+
+		_, err := client.Send(context.Background(), testRequest("0200", "000005"))
+		assert.ErrorIs(t, err, ErrClientClosed)
+	})
+}
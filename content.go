@@ -0,0 +1,115 @@
+package iso8583parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported field content types
+const (
+	ContentTypeNumeric             = "n"
+	ContentTypeAlpha               = "a"
+	ContentTypeAlphaNumeric        = "an"
+	ContentTypeAlphaNumericSpecial = "ans"
+	ContentTypeAlphaSpecial        = "as"
+	ContentTypeNumericSpecial      = "ns"
+	ContentTypeSpecial             = "s"
+	ContentTypeTrack2              = "z"
+	ContentTypeBinary              = "b"
+	ContentTypeSignedAmount        = "x+n"
+)
+
+// Check whether the content type in field spesification is supported
+// Empty content type is allowed and the field value will not be checked
+func isValidContentType(contentType string) bool {
+	switch strings.ToLower(contentType) {
+	case "", ContentTypeNumeric, ContentTypeAlpha, ContentTypeAlphaNumeric, ContentTypeAlphaNumericSpecial,
+		ContentTypeAlphaSpecial, ContentTypeNumericSpecial, ContentTypeSpecial, ContentTypeTrack2,
+		ContentTypeBinary, ContentTypeSignedAmount:
+		return true
+	}
+
+	return false
+}
+
+func isNumericChar(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlphaChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Special characters are the printable ASCII characters that are not alphabetic or numeric, including space
+func isSpecialChar(c byte) bool {
+	return c >= 0x20 && c <= 0x7e && !isNumericChar(c) && !isAlphaChar(c)
+}
+
+// Track 2 characters are 0x30 - 0x3F (digits and : ; < = > ?), with D as field separator and F as padding
+func isTrack2Char(c byte) bool {
+	return (c >= 0x30 && c <= 0x3f) || c == 'D' || c == 'd' || c == 'F' || c == 'f'
+}
+
+// Validate the field value against the content type in field spesification
+// Errors can occur if the value contains a character outside the character class of the content type
+func validateContent(field int, spec FieldSpec, value string) error {
+	contentType := strings.ToLower(spec.ContentType)
+
+	// Fixed alphabetic fields are right padded with spaces
+	if strings.ToLower(spec.LenType) == "fixed" && (contentType == ContentTypeAlpha || contentType == ContentTypeAlphaNumeric) {
+		value = strings.TrimRight(value, " ")
+	}
+
+	var valid func(c byte) bool
+	switch contentType {
+	case ContentTypeNumeric:
+		valid = isNumericChar
+	case ContentTypeAlpha:
+		valid = isAlphaChar
+	case ContentTypeAlphaNumeric:
+		valid = func(c byte) bool { return isAlphaChar(c) || isNumericChar(c) }
+	case ContentTypeAlphaNumericSpecial:
+		valid = func(c byte) bool { return isAlphaChar(c) || isNumericChar(c) || isSpecialChar(c) }
+	case ContentTypeAlphaSpecial:
+		valid = func(c byte) bool { return isAlphaChar(c) || isSpecialChar(c) }
+	case ContentTypeNumericSpecial:
+		valid = func(c byte) bool { return isNumericChar(c) || isSpecialChar(c) }
+	case ContentTypeSpecial:
+		valid = isSpecialChar
+	case ContentTypeTrack2:
+		valid = isTrack2Char
+	case ContentTypeSignedAmount:
+		if len(value) == 0 {
+			return nil
+		}
+		if value[0] != 'C' && value[0] != 'D' {
+			return &ContentTypeError{Field: field, ContentType: spec.ContentType, Position: 0, Char: value[0]}
+		}
+		for i := 1; i < len(value); i++ {
+			if !isNumericChar(value[i]) {
+				return &ContentTypeError{Field: field, ContentType: spec.ContentType, Position: i, Char: value[i]}
+			}
+		}
+		return nil
+	default:
+		// Binary and unspecified content types accept every byte
+		return nil
+	}
+
+	for i := 0; i < len(value); i++ {
+		if !valid(value[i]) {
+			return &ContentTypeError{Field: field, ContentType: spec.ContentType, Position: i, Char: value[i]}
+		}
+	}
+
+	return nil
+}
+
+// Create the fixed length value of a signed amount, the sign is kept in front and the amount is left padded with zero
+func padSignedAmount(data string, maxLen int) string {
+	if len(data) == 0 || (data[0] != 'C' && data[0] != 'D') {
+		return leftPad(data, maxLen, "0")
+	}
+
+	return fmt.Sprintf("%c%s", data[0], leftPad(data[1:], maxLen-1, "0"))
+}
@@ -0,0 +1,113 @@
+package iso8583parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		lenType     string
+		value       string
+		position    int
+	}{
+		{name: "Numeric", contentType: "n", value: "0123456789", position: -1},
+		{name: "Numeric invalid", contentType: "n", value: "12A4", position: 2},
+		{name: "Alpha", contentType: "a", value: "ABCxyz", position: -1},
+		{name: "Alpha invalid", contentType: "a", value: "AB1", position: 2},
+		{name: "Alphanumeric padded", contentType: "an", lenType: "fixed", value: "AB12  ", position: -1},
+		{name: "Alphanumeric invalid", contentType: "an", lenType: "llvar", value: "AB 12", position: 2},
+		{name: "Alphanumeric special", contentType: "ans", value: "Shop #1, Jakarta", position: -1},
+		{name: "Alphanumeric special invalid", contentType: "ans", value: "AB\n", position: 2},
+		{name: "Numeric special", contentType: "ns", value: "1234-5678", position: -1},
+		{name: "Numeric special invalid", contentType: "ns", value: "12X4", position: 2},
+		{name: "Special", contentType: "s", value: "*#-", position: -1},
+		{name: "Track 2", contentType: "z", value: "4111111111111111=2512101", position: -1},
+		{name: "Track 2 with separator D", contentType: "z", value: "4111111111111111D2512101", position: -1},
+		{name: "Track 2 invalid", contentType: "z", value: "4111^2512", position: 4},
+		{name: "Binary", contentType: "b", value: "\x00\xff\x10", position: -1},
+		{name: "Signed amount", contentType: "x+n", value: "C00001500", position: -1},
+		{name: "Signed amount invalid sign", contentType: "x+n", value: "X00001500", position: 0},
+		{name: "Signed amount invalid digit", contentType: "x+n", value: "D0000150A", position: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateContent(7, FieldSpec{ContentType: tt.contentType, LenType: tt.lenType}, tt.value)
+			if tt.position < 0 {
+				assert.Nil(t, err, "Error should be nil")
+				return
+			}
+
+			var contentErr *ContentTypeError
+			require.True(t, errors.As(err, &contentErr), "Expected content type error")
+			assert.ErrorIs(t, err, ErrInvalidContentType)
+			assert.Equal(t, 7, contentErr.Field, "Expected field to be equal")
+			assert.Equal(t, tt.position, contentErr.Position, "Expected position to be equal")
+		})
+	}
+}
+
+func TestSetFieldContentType(t *testing.T) {
+	t.Run("Rejected", func(t *testing.T) {
+		isoParser, err := NewFromSpec(SpecData1987)
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.SetField(3, "ABC")
+		var contentErr *ContentTypeError
+		require.True(t, errors.As(err, &contentErr), "Expected content type error")
+		assert.Equal(t, 3, contentErr.Field, "Expected field to be equal")
+		assert.Equal(t, 3, contentErr.Position, "Expected position to be equal")
+
+		_, err = isoParser.GetField(3)
+		assert.NotNil(t, err, "Expected field not to be set")
+	})
+
+	t.Run("Warning", func(t *testing.T) {
+		isoParser, err := NewFromSpec(SpecData1987, WithContentTypeWarnings())
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.SetField(3, "ABC")
+		assert.Nil(t, err, "Error should be nil")
+
+		warnings := isoParser.Warnings()
+		require.Len(t, warnings, 1, "Expected one warning")
+		assert.ErrorIs(t, warnings[0], ErrInvalidContentType)
+
+		isoParser.Reset()
+		assert.Empty(t, isoParser.Warnings(), "Expected warnings to be cleared")
+	})
+
+	t.Run("Signed amount padding", func(t *testing.T) {
+		isoParser, err := NewFromSpec(SpecData1987)
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.SetField(28, "D150")
+		assert.Nil(t, err, "Error should be nil")
+
+		bit28, _ := isoParser.GetField(28)
+		assert.Equal(t, "D00000150", bit28, "Expected Bit28 to be equal")
+	})
+}
+
+func TestUnmarshalContentType(t *testing.T) {
+	isoMsg := "0200" + "2000000000000000" + "12A456"
+
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+
+	err = isoParser.UnmarshalString(isoMsg)
+	assert.ErrorIs(t, err, ErrInvalidContentType)
+
+	isoParser, err = NewFromSpec(SpecData1987, WithContentTypeWarnings())
+	require.Nil(t, err, "Error should be nil")
+
+	err = isoParser.UnmarshalString(isoMsg)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Len(t, isoParser.Warnings(), 1, "Expected one warning")
+}
@@ -0,0 +1,195 @@
+package iso8583parser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ANSI escape codes of the colours of a dump
+const (
+	colorReset = "\x1b[0m"
+	colorField = "\x1b[36m" // cyan
+	colorSpec  = "\x1b[2m"  // dim
+	colorValue = "\x1b[32m" // green
+)
+
+// DumpOption configures the output of Dump
+type DumpOption func(d *dumper)
+
+// WithDumpColor colours the field numbers, spesifications and values with ANSI escape codes for terminals
+func WithDumpColor() DumpOption {
+	return func(d *dumper) {
+		d.color = true
+	}
+}
+
+// dumper writes the human readable lines of a message
+type dumper struct {
+	w     io.Writer
+	color bool
+	err   error
+}
+
+// Dump writes the header, MTI, bitmap and every present field with its label, content type, length and value.
+// The subfields of composite fields and the entries of TLV formatted fields are written below the field,
+// binary values are written as hex.
+// An error may occur if writing to w fails
+func (iso *Iso8583Data) Dump(w io.Writer, opts ...DumpOption) error {
+	d := &dumper{w: w}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	if iso.Spec.Header != nil {
+		header := iso.Header.Value
+		if strings.ToLower(iso.Spec.Header.Type) == HeaderTypeTPDU {
+			header = fmt.Sprintf("ID %s destination %s source %s", iso.Header.ID, iso.Header.Destination, iso.Header.Source)
+		}
+		d.line(0, "Header", "", header)
+	}
+
+	mti := iso.Mti.Get()
+	if iso.Mti.validate() == nil {
+		mti = fmt.Sprintf("%s (%s %s %s %s)", mti, iso.Mti.Version(), iso.Mti.Class(), iso.Mti.Function(), iso.Mti.Origin())
+	}
+	d.line(0, "MTI", "", mti)
+
+	fields := iso.GetAllFieldKeySorted()
+	d.line(0, "Bitmap", "", dumpBitmap(fields))
+
+	for _, field := range fields {
+		value, _ := iso.Elements.getElement(field)
+		spec := iso.Spec.Fields[field]
+		d.line(0, fmt.Sprintf("F%03d", field), describeFieldSpec(spec), dumpValue(spec, value))
+
+		if len(spec.Subfields) > 0 {
+			subfields, _ := iso.GetSubfields(field)
+			for _, sub := range GetSortedKeyFields(subfields) {
+				subSpec := spec.Subfields[sub]
+				d.line(1, fmt.Sprintf("F%03d.%d", field, sub), describeFieldSpec(subSpec), dumpValue(subSpec, subfields[sub]))
+			}
+		}
+
+		if spec.Format != "" {
+			tlvs, err := iso.GetTags(field)
+			if err != nil {
+				d.line(1, "TLV", "", err.Error())
+				continue
+			}
+			d.tlvs(1, strings.ToLower(spec.Format) == FormatBerTLV, tlvs)
+		}
+	}
+
+	return d.err
+}
+
+// String returns the dump of the message without colours
+func (iso *Iso8583Data) String() string {
+	var sb strings.Builder
+	iso.Dump(&sb)
+	return sb.String()
+}
+
+// Write the entries of a TLV field, the entries of constructed tags are indented below their tag
+func (d *dumper) tlvs(depth int, binary bool, tlvs []TLV) {
+	for _, tlv := range tlvs {
+		if len(tlv.Children) > 0 {
+			d.line(depth, tlv.Tag, "", "")
+			d.tlvs(depth+1, binary, tlv.Children)
+			continue
+		}
+
+		value := string(tlv.Value)
+		if binary || !isPrintable(value) {
+			value = strings.ToUpper(hex.EncodeToString(tlv.Value))
+		}
+		d.line(depth, tlv.Tag, "", value)
+	}
+}
+
+// Write one line of the dump, e.g. "F004 Amount, transaction [n 12 fixed]: 000000001500"
+func (d *dumper) line(depth int, name, spec, value string) {
+	if d.err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(d.paint(colorField, name))
+	if spec != "" {
+		sb.WriteString(" ")
+		sb.WriteString(d.paint(colorSpec, spec))
+	}
+	sb.WriteString(": ")
+	sb.WriteString(d.paint(colorValue, value))
+	sb.WriteString("\n")
+
+	_, d.err = io.WriteString(d.w, sb.String())
+}
+
+func (d *dumper) paint(color, text string) string {
+	if !d.color || text == "" {
+		return text
+	}
+
+	return color + text + colorReset
+}
+
+// Describe a field spesification as label, content type, maximum length and length type
+func describeFieldSpec(spec FieldSpec) string {
+	description := fmt.Sprintf("[%s %d %s]", spec.ContentType, spec.MaxLen, strings.ToLower(spec.LenType))
+	if spec.Label == "" {
+		return description
+	}
+
+	return spec.Label + " " + description
+}
+
+// Format a field value, binary values and values with unprintable characters are written as hex
+func dumpValue(spec FieldSpec, value string) string {
+	if spec.ContentType == "b" || !isPrintable(value) {
+		return strings.ToUpper(hex.EncodeToString([]byte(value)))
+	}
+
+	return value
+}
+
+func isPrintable(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < 0x20 || value[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Create the hex bitmap of the present fields, with the secondary and tertiary bitmap when they are needed
+func dumpBitmap(fields []int) string {
+	size := bitmapSizePrimary
+	for _, field := range fields {
+		if field > bitmapSizeSecondary {
+			size = bitmapSizeTertiary
+		} else if field > bitmapSizePrimary && size < bitmapSizeSecondary {
+			size = bitmapSizeSecondary
+		}
+	}
+
+	bitmap := make([]byte, size/8)
+	set := func(field int) {
+		bitmap[(field-1)/8] |= 0x80 >> ((field - 1) % 8)
+	}
+	for _, field := range fields {
+		set(field)
+	}
+	if size > bitmapSizePrimary {
+		set(1)
+	}
+	if size > bitmapSizeSecondary {
+		set(bitmapSizePrimary + 1)
+	}
+
+	return strings.ToUpper(hex.EncodeToString(bitmap))
+}
@@ -0,0 +1,79 @@
+package iso8583parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	isoParser, _ := NewFromSpec(SpecData1987)
+	isoParser.AddMTI("0400")
+	isoParser.SetField(4, "1500")
+	isoParser.SetField(11, "000123")
+	isoParser.SetField(52, "\x01\x02\xAB\xCD\x00\x00\x00\x00")
+	isoParser.SetField(55, "\x9F\x26\x02\x11\x22\x95\x01\x80")
+	isoParser.SetSubfield(90, 1, "0200")
+	isoParser.SetSubfield(90, 2, "000122")
+
+	expected := strings.Join([]string{
+		"MTI: 0400 (1987 reversal request acquirer)",
+		"Bitmap: 90200000000012000000004000000000",
+		"F004 Amount, transaction [n 12 fixed]: 000000001500",
+		"F011 System trace audit number [n 6 fixed]: 000123",
+		"F052 Personal identification number data [b 8 fixed]: 0102ABCD00000000",
+		"F055 ICC data - EMV having multiple tags [b 999 lllvar]: 9F26021122950180",
+		"  9F26: 1122",
+		"  95: 80",
+		"F090 Original data elements [n 42 fixed]: 020000012200000000000000000000000000000000",
+		"  F090.1 Original message type indicator [n 4 fixed]: 0200",
+		"  F090.2 Original system trace audit number [n 6 fixed]: 000122",
+		"  F090.3 Original transmission date & time [n 10 fixed]: 0000000000",
+		"  F090.4 Original acquiring institution identification code [n 11 fixed]: 00000000000",
+		"  F090.5 Original forwarding institution identification code [n 11 fixed]: 00000000000",
+		"",
+	}, "\n")
+
+	var buf bytes.Buffer
+	require.Nil(t, isoParser.Dump(&buf))
+	assert.Equal(t, expected, buf.String(), "Expected dump to be equal")
+	assert.Equal(t, expected, isoParser.String(), "Expected String to be the dump")
+	assert.Equal(t, expected, fmt.Sprint(isoParser), "Expected fmt to use String")
+}
+
+func TestDumpColor(t *testing.T) {
+	isoParser, _ := NewFromSpec(SpecData1987)
+	isoParser.AddMTI("0800")
+	isoParser.SetField(70, "301")
+
+	var buf bytes.Buffer
+	require.Nil(t, isoParser.Dump(&buf, WithDumpColor()))
+	assert.Contains(t, buf.String(), "\x1b[36mF070\x1b[0m \x1b[2mNetwork management information code [n 3 fixed]\x1b[0m: \x1b[32m301\x1b[0m\n")
+}
+
+func TestDumpHeader(t *testing.T) {
+	spec := SpecData1987
+	spec.Header = &HeaderSpec{Type: HeaderTypeTPDU}
+	isoParser, _ := NewFromSpec(spec)
+	isoParser.Header = Header{ID: "60", Destination: "0012", Source: "0000"}
+
+	dump := isoParser.String()
+	assert.True(t, strings.HasPrefix(dump, "Header: ID 60 destination 0012 source 0000\nMTI: \nBitmap: 0000000000000000\n"), dump)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestDumpWriteError(t *testing.T) {
+	isoParser, _ := NewFromSpec(SpecData1987)
+	isoParser.AddMTI("0200")
+	assert.NotNil(t, isoParser.Dump(failingWriter{}), "Expected the write error")
+}
@@ -0,0 +1,192 @@
+package iso8583parser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Supported field encodings. An empty encoding in FieldSpec is treated as ASCII.
+const (
+	EncodingASCII  = "ascii"
+	EncodingEBCDIC = "ebcdic"
+	EncodingBCD    = "bcd"  // packed BCD, right-justified (odd length padded with a leading zero nibble)
+	EncodingLBCD   = "lbcd" // packed BCD, left-justified (odd length padded with a trailing zero nibble)
+	EncodingBinary = "binary"
+)
+
+// Encoder converts a field value between its textual representation and the bytes sent over the wire
+type Encoder interface {
+	// Encode converts the value into wire bytes
+	Encode(value string) ([]byte, error)
+
+	// Decode reads a value of the given length (in characters or digits) from the beginning of data
+	// and returns the value with the number of bytes read
+	Decode(data []byte, length int) (value string, read int, err error)
+}
+
+// Get the encoder for the encoding name in field spesification
+func getEncoder(name string) (Encoder, error) {
+	switch strings.ToLower(name) {
+	case "", EncodingASCII:
+		return asciiEncoder{}, nil
+	case EncodingEBCDIC:
+		return ebcdicEncoder{}, nil
+	case EncodingBCD:
+		return bcdEncoder{leftJustified: false}, nil
+	case EncodingLBCD:
+		return bcdEncoder{leftJustified: true}, nil
+	case EncodingBinary:
+		return binaryEncoder{}, nil
+	}
+
+	return nil, fmt.Errorf("%s is an invalid Encoding", name)
+}
+
+// asciiEncoder writes the value as it is, this is the default behaviour
+type asciiEncoder struct{}
+
+func (asciiEncoder) Encode(value string) ([]byte, error) {
+	return []byte(value), nil
+}
+
+func (asciiEncoder) Decode(data []byte, length int) (string, int, error) {
+	if length < 0 || length > len(data) {
+		return "", 0, ErrNotEnoughData
+	}
+
+	return string(data[:length]), length, nil
+}
+
+// binaryEncoder writes the raw bytes of the value, the length is counted in bytes
+type binaryEncoder struct{}
+
+func (binaryEncoder) Encode(value string) ([]byte, error) {
+	return []byte(value), nil
+}
+
+func (binaryEncoder) Decode(data []byte, length int) (string, int, error) {
+	if length < 0 || length > len(data) {
+		return "", 0, ErrNotEnoughData
+	}
+
+	return string(data[:length]), length, nil
+}
+
+// ebcdicEncoder translates the value between ASCII and EBCDIC (code page 037)
+type ebcdicEncoder struct{}
+
+func (ebcdicEncoder) Encode(value string) ([]byte, error) {
+	return asciiToEbcdic(value)
+}
+
+func (ebcdicEncoder) Decode(data []byte, length int) (string, int, error) {
+	if length < 0 || length > len(data) {
+		return "", 0, ErrNotEnoughData
+	}
+
+	value, err := ebcdicToAscii(data[:length])
+	if err != nil {
+		return "", 0, err
+	}
+
+	return value, length, nil
+}
+
+// bcdEncoder packs two digits per byte
+type bcdEncoder struct {
+	leftJustified bool
+}
+
+func (e bcdEncoder) Encode(value string) ([]byte, error) {
+	if len(value)%2 != 0 {
+		if e.leftJustified {
+			value += "0"
+		} else {
+			value = "0" + value
+		}
+	}
+
+	packed, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BCD value: %w", err)
+	}
+
+	return packed, nil
+}
+
+func (e bcdEncoder) Decode(data []byte, length int) (string, int, error) {
+	read := (length + 1) / 2
+	if length < 0 || read > len(data) {
+		return "", 0, ErrNotEnoughData
+	}
+
+	value := strings.ToUpper(hex.EncodeToString(data[:read]))
+	if length%2 != 0 {
+		if e.leftJustified {
+			value = value[:length]
+		} else {
+			value = value[1:]
+		}
+	}
+
+	return value, read, nil
+}
+
+// ASCII characters and their EBCDIC (code page 037) counterpart
+const (
+	ebcdicAsciiChars = " .<(+|&!$*);-/,%_>?`:#@'=\"abcdefghijklmnopqr~stuvwxyz^[]{ABCDEFGHI}JKLMNOPQR\\STUVWXYZ0123456789"
+	ebcdicCodeChars  = "\x40\x4b\x4c\x4d\x4e\x4f\x50\x5a\x5b\x5c\x5d\x5e\x60\x61\x6b\x6c\x6d\x6e\x6f\x79\x7a\x7b\x7c\x7d\x7e\x7f" +
+		"\x81\x82\x83\x84\x85\x86\x87\x88\x89\x91\x92\x93\x94\x95\x96\x97\x98\x99\xa1\xa2\xa3\xa4\xa5\xa6\xa7\xa8\xa9" +
+		"\xb0\xba\xbb\xc0\xc1\xc2\xc3\xc4\xc5\xc6\xc7\xc8\xc9\xd0\xd1\xd2\xd3\xd4\xd5\xd6\xd7\xd8\xd9" +
+		"\xe0\xe2\xe3\xe4\xe5\xe6\xe7\xe8\xe9\xf0\xf1\xf2\xf3\xf4\xf5\xf6\xf7\xf8\xf9"
+)
+
+var (
+	asciiToEbcdicTable [256]int
+	ebcdicToAsciiTable [256]int
+)
+
+func init() {
+	for i := range asciiToEbcdicTable {
+		asciiToEbcdicTable[i] = -1
+		ebcdicToAsciiTable[i] = -1
+	}
+
+	asciiToEbcdicTable[0] = 0
+	ebcdicToAsciiTable[0] = 0
+	for i := 0; i < len(ebcdicAsciiChars); i++ {
+		asciiToEbcdicTable[ebcdicAsciiChars[i]] = int(ebcdicCodeChars[i])
+		ebcdicToAsciiTable[ebcdicCodeChars[i]] = int(ebcdicAsciiChars[i])
+	}
+}
+
+// Convert ASCII text to EBCDIC bytes
+// Errors can occur if the text contains a character that has no EBCDIC counterpart
+func asciiToEbcdic(str string) ([]byte, error) {
+	out := make([]byte, len(str))
+	for i := 0; i < len(str); i++ {
+		c := asciiToEbcdicTable[str[i]]
+		if c < 0 {
+			return nil, fmt.Errorf("invalid EBCDIC character at index %d: %q", i, str[i])
+		}
+		out[i] = byte(c)
+	}
+
+	return out, nil
+}
+
+// Convert EBCDIC bytes to ASCII text
+// Errors can occur if the data contains a byte that has no ASCII counterpart
+func ebcdicToAscii(data []byte) (string, error) {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		c := ebcdicToAsciiTable[b]
+		if c < 0 {
+			return "", fmt.Errorf("invalid EBCDIC byte at index %d: 0x%02x", i, b)
+		}
+		out[i] = byte(c)
+	}
+
+	return string(out), nil
+}
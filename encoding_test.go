@@ -0,0 +1,114 @@
+package iso8583parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEncoder(t *testing.T) {
+	t.Run("Default ASCII", func(t *testing.T) {
+		encoder, err := getEncoder("")
+		assert.Nil(t, err, "Error should be nil")
+		assert.Equal(t, asciiEncoder{}, encoder, "Expected ASCII encoder")
+	})
+
+	t.Run("Invalid encoding", func(t *testing.T) {
+		_, err := getEncoder("utf16")
+		assert.NotNil(t, err, "Expected error encoding")
+	})
+}
+
+func TestBCDEncoder(t *testing.T) {
+	t.Run("Right justified", func(t *testing.T) {
+		encoder, _ := getEncoder(EncodingBCD)
+		packed, err := encoder.Encode("12345")
+		assert.Nil(t, err, "Error should be nil")
+		assert.Equal(t, []byte{0x01, 0x23, 0x45}, packed, "Expected packed bytes to be equal")
+
+		value, read, err := encoder.Decode(packed, 5)
+		assert.Nil(t, err, "Error should be nil")
+		assert.Equal(t, 3, read, "Expected read bytes to be equal")
+		assert.Equal(t, "12345", value, "Expected value to be equal")
+	})
+
+	t.Run("Left justified", func(t *testing.T) {
+		encoder, _ := getEncoder(EncodingLBCD)
+		packed, err := encoder.Encode("12345")
+		assert.Nil(t, err, "Error should be nil")
+		assert.Equal(t, []byte{0x12, 0x34, 0x50}, packed, "Expected packed bytes to be equal")
+
+		value, read, err := encoder.Decode(packed, 5)
+		assert.Nil(t, err, "Error should be nil")
+		assert.Equal(t, 3, read, "Expected read bytes to be equal")
+		assert.Equal(t, "12345", value, "Expected value to be equal")
+	})
+
+	t.Run("Not enough data", func(t *testing.T) {
+		encoder, _ := getEncoder(EncodingBCD)
+		_, _, err := encoder.Decode([]byte{0x12}, 4)
+		assert.ErrorIs(t, err, ErrNotEnoughData)
+	})
+}
+
+func TestEBCDICEncoder(t *testing.T) {
+	encoder, _ := getEncoder(EncodingEBCDIC)
+	encoded, err := encoder.Encode("AB 12")
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, []byte{0xc1, 0xc2, 0x40, 0xf1, 0xf2}, encoded, "Expected EBCDIC bytes to be equal")
+
+	value, read, err := encoder.Decode(encoded, 5)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 5, read, "Expected read bytes to be equal")
+	assert.Equal(t, "AB 12", value, "Expected value to be equal")
+}
+
+func TestMarshalEncodedFields(t *testing.T) {
+	specData := SpecData{
+		Fields: map[int]FieldSpec{
+			0:  {ContentType: "n", LenType: "fixed", MaxLen: 4, Encoding: EncodingBCD},
+			2:  {ContentType: "n", LenType: "llvar", MaxLen: 19, Encoding: EncodingBCD},
+			3:  {ContentType: "n", LenType: "fixed", MaxLen: 6, Encoding: EncodingBCD},
+			41: {ContentType: "ans", LenType: "fixed", MaxLen: 8, Encoding: EncodingEBCDIC},
+			52: {ContentType: "b", LenType: "fixed", MaxLen: 8, Encoding: EncodingBinary},
+		},
+	}
+
+	isoParser, err := NewFromSpec(specData)
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.AddMTI("0200")
+	isoParser.SetField(2, "4111111111111")
+	isoParser.SetField(3, "1")
+	isoParser.SetField(41, "TID1")
+	isoParser.SetField(52, "\x01\x02\x03\x04\x05\x06\x07\x08")
+
+	isoMsg, err := isoParser.Marshal()
+	require.Nil(t, err, "Error should be nil")
+
+	expected := []byte{0x02, 0x00}
+	expected = append(expected, []byte("6000000000801000")...)
+	expected = append(expected, []byte("13")...)
+	expected = append(expected, 0x04, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11)
+	expected = append(expected, 0x00, 0x00, 0x01)
+	expected = append(expected, 0xe3, 0xc9, 0xc4, 0xf1, 0x40, 0x40, 0x40, 0x40)
+	expected = append(expected, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08)
+	require.Equal(t, expected, isoMsg, "Expected iso message to be equal")
+
+	unpacked, err := NewFromSpec(specData)
+	require.Nil(t, err, "Error should be nil")
+
+	err = unpacked.Unmarshal(isoMsg)
+	require.Nil(t, err, "Error should be nil")
+
+	bit2, _ := unpacked.GetField(2)
+	bit3, _ := unpacked.GetField(3)
+	bit41, _ := unpacked.GetField(41)
+	bit52, _ := unpacked.GetField(52)
+	require.Equal(t, "0200", unpacked.Mti.Get(), "Expected MTI to be equal")
+	require.Equal(t, "4111111111111", bit2, "Expected Bit2 to be equal")
+	require.Equal(t, "000001", bit3, "Expected Bit3 to be equal")
+	require.Equal(t, "TID1    ", bit41, "Expected Bit41 to be equal")
+	require.Equal(t, "\x01\x02\x03\x04\x05\x06\x07\x08", bit52, "Expected Bit52 to be equal")
+}
@@ -2,16 +2,161 @@ package iso8583parser
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 var (
 	ErrInvalidMtiLength           = errors.New("MTI must be length (4)")
 	ErrInvalidMtiInteger          = errors.New("MTI can only contain integers")
+	ErrUndefinedMti               = errors.New("MTI is not defined in its version")
+	ErrNoResponseMti              = errors.New("MTI has no response")
+	ErrNoReversalMti              = errors.New("MTI can not be reversed")
+	ErrNoAdviceMti                = errors.New("MTI has no advice")
 	ErrEmptySpec                  = errors.New("specification is empty")
 	ErrSpecMinHasOneField         = errors.New("specification minimum has one field or more without field 0 and 1")
+	ErrUnsupportedMtiVersion      = errors.New("no specification for MTI version")
 	ErrInvalidBitLength           = errors.New("bit length must be multiple of 4")
 	ErrDataToShortSecondaryBitmap = errors.New("data too short for secondary bitmap")
 	ErrDataToShortTertiaryBitmap  = errors.New("data too short for tertiary bitmap")
 	ErrIsoMessageTooShort         = errors.New("data iso message too short")
 	ErrEmptyDataElements          = errors.New("elements data empty")
+	ErrNotEnoughData              = errors.New("not enough data to decode")
+	ErrInvalidLenType             = errors.New("invalid LenType")
+	ErrInvalidContentType         = errors.New("invalid content type")
+	ErrFieldTooLong               = errors.New("data exceeds field max length")
+	ErrFieldTooShort              = errors.New("data is shorter than field min length")
+	ErrInvalidDate                = errors.New("date does not exist")
+	ErrInvalidFieldNumber         = errors.New("field number must be between 2 and 192")
+	ErrNoFieldSpec                = errors.New("no field spec")
+	ErrPrefixTooShort             = errors.New("data too short for length prefix")
+	ErrInvalidPrefix              = errors.New("invalid length prefix")
+	ErrValueTooShort              = errors.New("data too short for field value")
+	ErrMissingMandatoryField      = errors.New("mandatory field is missing")
+	ErrNoSubfieldSpec             = errors.New("no subfield spec")
+	ErrSubfieldDataLeft           = errors.New("data left after the last subfield")
+	ErrNotTLVField                = errors.New("field is not TLV formatted")
+	ErrInvalidStruct              = errors.New("invalid struct for iso8583 tags")
+	ErrFrameTooLarge              = errors.New("frame exceeds max size")
+	ErrInvalidFrameHeader         = errors.New("invalid frame header")
+	ErrInvalidHeader              = errors.New("invalid message header")
+	ErrNoMatchFields              = errors.New("message has none of the match fields")
+	ErrDuplicateRequest           = errors.New("request with the same match fields is waiting")
+	ErrSendTimeout                = errors.New("response not received in time")
+	ErrClientClosed               = errors.New("client connection closed")
+	ErrServerClosed               = errors.New("server closed")
+	ErrNoHandler                  = errors.New("no handler for MTI")
+	ErrHandlerPanic               = errors.New("handler panic")
+	ErrNotSignedOn                = errors.New("link is not signed on")
+	ErrNetworkRequestDeclined     = errors.New("network management request declined")
+	ErrNoConnection               = errors.New("no usable connection in the pool")
+	ErrPoolClosed                 = errors.New("pool closed")
+	ErrSAFMessageDropped          = errors.New("store-and-forward message dropped")
 )
+
+// ContentTypeError describes a field value that contains a character outside the character class of its content type
+type ContentTypeError struct {
+	Field       int
+	ContentType string
+	Position    int
+	Char        byte
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("invalid character %q at position %d for content type %s", e.Char, e.Position, e.ContentType)
+}
+
+func (e *ContentTypeError) Unwrap() error {
+	return ErrInvalidContentType
+}
+
+// LengthError describes a field value with a length outside the MinLen and MaxLen of the field spesification
+type LengthError struct {
+	Field  int
+	MinLen int
+	MaxLen int
+	Length int
+	Err    error
+}
+
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("data length %d is outside the allowed length %d..%d", e.Length, e.MinLen, e.MaxLen)
+}
+
+func (e *LengthError) Unwrap() error {
+	return e.Err
+}
+
+// Phase of packing or unpacking an iso message where a FieldError occurred
+type Phase string
+
+const (
+	PhaseHeader Phase = "header"
+	PhaseMTI    Phase = "mti"
+	PhaseBitmap Phase = "bitmap"
+	PhasePrefix Phase = "prefix"
+	PhaseValue  Phase = "value"
+)
+
+// FieldError describes a failure while setting, packing or unpacking a field.
+// Field is 0 for the MTI and 1 for the bitmap, Subfield is 0 when the failure is not in a subfield.
+// Offset is the byte position in the iso message, or -1 when the error did not occur while reading a message.
+// Expected and Actual are lengths, set when the failure is caused by a length mismatch.
+type FieldError struct {
+	Field    int
+	Subfield int
+	Phase    Phase
+	Offset   int
+	Expected int
+	Actual   int
+	Err      error
+}
+
+func (e *FieldError) Error() string {
+	field := fmt.Sprintf("field %d", e.Field)
+	if e.Subfield > 0 {
+		field = fmt.Sprintf("field %d.%d", e.Field, e.Subfield)
+	}
+
+	if e.Offset < 0 {
+		return fmt.Sprintf("%s %s: %v", field, e.Phase, e.Err)
+	}
+
+	return fmt.Sprintf("%s %s at offset %d: %v", field, e.Phase, e.Offset, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Create FieldError for the field, the expected and actual length are taken from a LengthError
+func newFieldError(field int, phase Phase, offset int, err error) *FieldError {
+	fieldErr := &FieldError{Field: field, Phase: phase, Offset: offset, Err: err}
+
+	var lengthErr *LengthError
+	if errors.As(err, &lengthErr) {
+		fieldErr.Actual = lengthErr.Length
+		fieldErr.Expected = lengthErr.MaxLen
+		if errors.Is(lengthErr.Err, ErrFieldTooShort) {
+			fieldErr.Expected = lengthErr.MinLen
+		}
+	}
+
+	return fieldErr
+}
+
+// ValidationErrors contains every violation found while validating an iso message
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d validation errors: %s", len(e), strings.Join(messages, "; "))
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	return e
+}
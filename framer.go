@@ -0,0 +1,144 @@
+package iso8583parser
+
+import (
+	"fmt"
+	"io"
+)
+
+// DefaultMaxFrameSize is the maximum payload size of a frame when Framer.MaxSize is not set
+const DefaultMaxFrameSize = 8192
+
+// FrameHeader describes the length header in front of every message on a stream,
+// the length is encoded like the length prefix of a variable length field
+type FrameHeader struct {
+	LenEncoding string // ascii (default), ebcdic, bcd or binary
+	LenDigits   int    // number of length digits, a binary length has one byte for every two digits
+}
+
+// Built-in frame length headers
+var (
+	FrameHeaderBinary2 = FrameHeader{LenEncoding: EncodingBinary, LenDigits: 4} // 2 bytes big-endian binary length
+	FrameHeaderASCII4  = FrameHeader{LenEncoding: EncodingASCII, LenDigits: 4}  // 4 ASCII digits length
+	FrameHeaderBCD2    = FrameHeader{LenEncoding: EncodingBCD, LenDigits: 4}    // 2 bytes BCD length
+)
+
+// Framer reads and writes length framed messages on a stream like a TCP connection.
+// The length in the header counts the payload only, the payload is the packed message as returned by Marshal.
+// A Framer has no state so one value can be shared by many connections
+type Framer struct {
+	Header FrameHeader
+
+	// MaxSize limits the payload size of a frame, frames announcing a larger payload are rejected before they are read.
+	// DefaultMaxFrameSize is used when MaxSize is 0
+	MaxSize int
+}
+
+// Create a new Framer with a frame header and the default maximum payload size
+func NewFramer(header FrameHeader) Framer {
+	return Framer{Header: header}
+}
+
+func (f Framer) maxSize() int {
+	if f.MaxSize > 0 {
+		return f.MaxSize
+	}
+
+	return DefaultMaxFrameSize
+}
+
+// Get the prefixer and the size in bytes of the frame header
+func (f Framer) header() (Prefixer, int, error) {
+	prefixer, err := getPrefixer(f.Header.LenEncoding)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	empty, err := prefixer.EncodeLength(0, f.Header.LenDigits)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return prefixer, len(empty), nil
+}
+
+// Read one frame from r returning its payload.
+// An error may occur if r fails, if the stream ends in the middle of a frame (io.ErrUnexpectedEOF)
+// and if the frame is larger than the maximum size (ErrFrameTooLarge).
+// io.EOF is returned when the stream ends before a new frame
+func (f Framer) ReadFrame(r io.Reader) ([]byte, error) {
+	prefixer, size, err := f.header()
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, size)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	length, _, err := prefixer.DecodeLength(header, f.Header.LenDigits)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFrameHeader, err)
+	}
+
+	if length > f.maxSize() {
+		return nil, fmt.Errorf("%w: %d bytes exceeds %d bytes", ErrFrameTooLarge, length, f.maxSize())
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// Write the payload as one frame to w, the header and the payload are written with a single Write call
+// so frames of concurrent writers on a connection are not mixed up.
+// An error may occur if w fails and if the payload is larger than the maximum size or the header length
+func (f Framer) WriteFrame(w io.Writer, payload []byte) error {
+	if len(payload) > f.maxSize() {
+		return fmt.Errorf("%w: %d bytes exceeds %d bytes", ErrFrameTooLarge, len(payload), f.maxSize())
+	}
+
+	prefixer, size, err := f.header()
+	if err != nil {
+		return err
+	}
+
+	header, err := prefixer.EncodeLength(len(payload), f.Header.LenDigits)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFrameTooLarge, err)
+	}
+
+	frame := make([]byte, 0, size+len(payload))
+	frame = append(frame, header...)
+	frame = append(frame, payload...)
+
+	_, err = w.Write(frame)
+	return err
+}
+
+// Read one frame from r and unmarshal its payload into iso, iso is reset before
+func (f Framer) ReadMessage(r io.Reader, iso *Iso8583Data) error {
+	payload, err := f.ReadFrame(r)
+	if err != nil {
+		return err
+	}
+
+	iso.Reset()
+	return iso.Unmarshal(payload)
+}
+
+// Marshal iso and write it as one frame to w
+func (f Framer) WriteMessage(w io.Writer, iso *Iso8583Data) error {
+	payload, err := iso.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return f.WriteFrame(w, payload)
+}
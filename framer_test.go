@@ -0,0 +1,92 @@
+package iso8583parser
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameHeaders(t *testing.T) {
+	payload := []byte("0800" + "0020000000000000" + "123456")
+
+	tests := []struct {
+		name   string
+		header FrameHeader
+		expect []byte
+	}{
+		{"Binary", FrameHeaderBinary2, []byte{0x00, 0x1A}},
+		{"ASCII", FrameHeaderASCII4, []byte("0026")},
+		{"BCD", FrameHeaderBCD2, []byte{0x00, 0x26}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			framer := NewFramer(tt.header)
+
+			var buf bytes.Buffer
+			require.Nil(t, framer.WriteFrame(&buf, payload))
+			assert.Equal(t, append(tt.expect, payload...), buf.Bytes(), "Expected frame to be equal")
+
+			read, err := framer.ReadFrame(&buf)
+			require.Nil(t, err, "Error should be nil")
+			assert.Equal(t, payload, read, "Expected payload to be equal")
+
+			_, err = framer.ReadFrame(&buf)
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestFrameErrors(t *testing.T) {
+	framer := Framer{Header: FrameHeaderBinary2, MaxSize: 16}
+
+	t.Run("Frame too large to read", func(t *testing.T) {
+		_, err := framer.ReadFrame(bytes.NewReader([]byte{0xFF, 0xFF}))
+		assert.ErrorIs(t, err, ErrFrameTooLarge)
+	})
+
+	t.Run("Frame too large to write", func(t *testing.T) {
+		err := framer.WriteFrame(io.Discard, make([]byte, 17))
+		assert.ErrorIs(t, err, ErrFrameTooLarge)
+	})
+
+	t.Run("Truncated frame", func(t *testing.T) {
+		_, err := framer.ReadFrame(bytes.NewReader([]byte{0x00, 0x05, 'A'}))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("Invalid header", func(t *testing.T) {
+		_, err := NewFramer(FrameHeaderASCII4).ReadFrame(bytes.NewReader([]byte("00X1")))
+		assert.ErrorIs(t, err, ErrInvalidFrameHeader)
+	})
+}
+
+func TestFramerPipe(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	framer := NewFramer(FrameHeaderBinary2)
+
+	request, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+	request.AddMTI("0800")
+	request.SetField(11, "123456")
+	request.SetField(70, "301")
+
+	go func() {
+		assert.Nil(t, framer.WriteMessage(client, request))
+	}()
+
+	received, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, framer.ReadMessage(server, received))
+
+	assert.Equal(t, "0800", received.Mti.Get(), "Expected MTI to be equal")
+	bit70, _ := received.GetField(70)
+	assert.Equal(t, "301", bit70, "Expected Bit70 to be equal")
+}
@@ -0,0 +1,152 @@
+package iso8583parser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Supported message header types
+const (
+	HeaderTypeTPDU  = "tpdu"  // 5 bytes TPDU: ID, destination address and source address
+	HeaderTypeFixed = "fixed" // fixed length proprietary header, e.g. "ISO025000050"
+)
+
+// TPDULength is the length in bytes of a TPDU header
+const TPDULength = 5
+
+// HeaderSpec describes the header in front of the MTI
+type HeaderSpec struct {
+	Type     string `yaml:"Type"`     // tpdu or fixed
+	Length   int    `yaml:"Length"`   // length of a fixed header, a TPDU is always 5 bytes
+	Encoding string `yaml:"Encoding"` // encoding of a fixed header, ascii (default) or ebcdic
+}
+
+// Header is the parsed header in front of the MTI.
+// The TPDU parts are written as hex (e.g. ID "60" and destination "0012"), a fixed header is kept in Value
+type Header struct {
+	ID          string
+	Destination string
+	Source      string
+	Value       string
+}
+
+// Swap returns the header of the response, the destination and source address of a TPDU are exchanged
+func (h Header) Swap() Header {
+	h.Destination, h.Source = h.Source, h.Destination
+	return h
+}
+
+// Check the header type and length of the header spesification
+func (h *HeaderSpec) validate() error {
+	if h == nil {
+		return nil
+	}
+
+	switch strings.ToLower(h.Type) {
+	case HeaderTypeTPDU:
+		return nil
+	case HeaderTypeFixed:
+		if h.Length < 1 {
+			return fmt.Errorf("fixed header Length must be greater than 0")
+		}
+		encoding := strings.ToLower(h.Encoding)
+		if encoding != "" && encoding != EncodingASCII && encoding != EncodingEBCDIC {
+			return fmt.Errorf("%s is an invalid header Encoding", h.Encoding)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%s is an invalid header Type", h.Type)
+}
+
+// Get the length in bytes of the header, 0 when there is no header
+func (h *HeaderSpec) length() int {
+	if h == nil {
+		return 0
+	}
+
+	if strings.ToLower(h.Type) == HeaderTypeTPDU {
+		return TPDULength
+	}
+
+	return h.Length
+}
+
+// Encode the header according to the header spesification, absent TPDU parts are written as zero
+// and a short fixed header is right padded with spaces
+func (h *HeaderSpec) encode(header Header) ([]byte, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	if strings.ToLower(h.Type) == HeaderTypeTPDU {
+		buf := make([]byte, 0, TPDULength)
+		for _, part := range []struct {
+			name  string
+			value string
+			size  int
+		}{{"ID", header.ID, 1}, {"destination", header.Destination, 2}, {"source", header.Source, 2}} {
+			if len(part.value) > part.size*2 {
+				return nil, fmt.Errorf("%w: TPDU %s %q exceeds %d bytes", ErrInvalidHeader, part.name, part.value, part.size)
+			}
+			b, err := hex.DecodeString(leftPad(part.value, part.size*2, "0"))
+			if err != nil {
+				return nil, fmt.Errorf("%w: TPDU %s %q is not hex", ErrInvalidHeader, part.name, part.value)
+			}
+			buf = append(buf, b...)
+		}
+		return buf, nil
+	}
+
+	if len(header.Value) > h.Length {
+		return nil, fmt.Errorf("%w: header %q exceeds length %d", ErrInvalidHeader, header.Value, h.Length)
+	}
+
+	encoder, err := getEncoder(h.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	return encoder.Encode(rightPad(header.Value, h.Length, " "))
+}
+
+// Decode the header from the beginning of data returning the number of bytes read
+func (h *HeaderSpec) decode(data []byte) (Header, int, error) {
+	if h == nil {
+		return Header{}, 0, nil
+	}
+
+	if len(data) < h.length() {
+		return Header{}, 0, ErrNotEnoughData
+	}
+
+	if strings.ToLower(h.Type) == HeaderTypeTPDU {
+		return Header{
+			ID:          strings.ToUpper(hex.EncodeToString(data[0:1])),
+			Destination: strings.ToUpper(hex.EncodeToString(data[1:3])),
+			Source:      strings.ToUpper(hex.EncodeToString(data[3:5])),
+		}, TPDULength, nil
+	}
+
+	encoder, err := getEncoder(h.Encoding)
+	if err != nil {
+		return Header{}, 0, err
+	}
+
+	value, read, err := encoder.Decode(data, h.Length)
+	if err != nil {
+		return Header{}, 0, err
+	}
+
+	return Header{Value: value}, read, nil
+}
+
+// Check whether two header spesifications describe the same header
+func sameHeaderSpec(a, b *HeaderSpec) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
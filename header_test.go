@@ -0,0 +1,86 @@
+package iso8583parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func specWithHeader(header *HeaderSpec) SpecData {
+	return SpecData{
+		Fields: map[int]FieldSpec{
+			3:  {ContentType: "n", LenType: "fixed", MaxLen: 6},
+			11: {ContentType: "n", LenType: "fixed", MaxLen: 6},
+		},
+		Header: header,
+	}
+}
+
+func TestTPDUHeader(t *testing.T) {
+	spec := specWithHeader(&HeaderSpec{Type: HeaderTypeTPDU})
+
+	isoParser, err := NewFromSpec(spec)
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.Header = Header{ID: "60", Destination: "0012", Source: "0000"}
+	isoParser.AddMTI("0200")
+	isoParser.SetField(11, "000001")
+
+	isoMsg, err := isoParser.Marshal()
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, []byte{0x60, 0x00, 0x12, 0x00, 0x00}, isoMsg[:TPDULength], "Expected TPDU to be equal")
+	assert.Equal(t, "0200"+"0020000000000000"+"000001", string(isoMsg[TPDULength:]), "Expected iso message to be equal")
+
+	unpacked, err := NewFromSpec(spec)
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, unpacked.Unmarshal(isoMsg))
+	assert.Equal(t, Header{ID: "60", Destination: "0012", Source: "0000"}, unpacked.Header, "Expected header to be equal")
+	assert.Equal(t, "0200", unpacked.Mti.Get(), "Expected MTI to be equal")
+
+	swapped := unpacked.Header.Swap()
+	assert.Equal(t, Header{ID: "60", Destination: "0000", Source: "0012"}, swapped, "Expected addresses to be swapped")
+
+	err = unpacked.Unmarshal([]byte{0x60, 0x00})
+	var fieldErr *FieldError
+	require.True(t, errors.As(err, &fieldErr), "Expected field error")
+	assert.Equal(t, PhaseHeader, fieldErr.Phase, "Expected phase to be equal")
+
+	unpacked.Header.Source = "12345"
+	_, err = unpacked.Marshal()
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestFixedHeader(t *testing.T) {
+	spec := specWithHeader(&HeaderSpec{Type: HeaderTypeFixed, Length: 12})
+
+	isoParser, err := NewFromSpec(spec)
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.Header = Header{Value: "ISO025000050"}
+	isoParser.AddMTI("0200")
+	isoParser.SetField(3, "000000")
+
+	isoMsg, err := isoParser.MarshalString()
+	require.Nil(t, err, "Error should be nil")
+	require.Equal(t, "ISO025000050"+"0200"+"2000000000000000"+"000000", isoMsg, "Expected iso message to be equal")
+
+	unpacked, err := NewFromSpec(spec)
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, unpacked.UnmarshalString(isoMsg))
+	assert.Equal(t, "ISO025000050", unpacked.Header.Value, "Expected header to be equal")
+
+	err = unpacked.UnmarshalString("ISO025000050" + "0200" + "20000000")
+	var fieldErr *FieldError
+	require.True(t, errors.As(err, &fieldErr), "Expected field error")
+	assert.Equal(t, 12, fieldErr.Offset, "Expected offset to be after the header")
+}
+
+func TestHeaderSpecValidation(t *testing.T) {
+	_, err := NewFromSpec(specWithHeader(&HeaderSpec{Type: "trailer"}))
+	assert.NotNil(t, err, "Expected error invalid header type")
+
+	_, err = NewFromSpec(specWithHeader(&HeaderSpec{Type: HeaderTypeFixed}))
+	assert.NotNil(t, err, "Expected error fixed header without length")
+}
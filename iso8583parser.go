@@ -1,11 +1,11 @@
 package iso8583parser
 
 import (
-	"encoding/hex"
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 const BitmapLength = 16
@@ -31,8 +31,9 @@ const (
 
 // ElementsData object
 type ElementsData struct {
-	mu       sync.RWMutex
-	elements map[int]string
+	mu          sync.RWMutex
+	elements    map[int]string
+	subelements map[int]map[int]string
 }
 
 // setElement addd element data based on a specific field
@@ -51,6 +52,23 @@ func (e *ElementsData) createElement(data string, len int, padType, padValue str
 	return rightPad(data, len, padValue)
 }
 
+// createFieldElement create the element data of a fixed length field by padding the data based on the field spesification
+func (e *ElementsData) createFieldElement(spec FieldSpec, data string) string {
+	if strings.ToLower(spec.LenType) != "fixed" {
+		return data
+	}
+
+	if spec.ContentType == "n" {
+		return e.createElement(data, spec.MaxLen, padTypeLeft, "0")
+	} else if spec.ContentType == ContentTypeSignedAmount {
+		return padSignedAmount(data, spec.MaxLen)
+	} else if strings.ToLower(spec.Encoding) == EncodingBinary {
+		return e.createElement(data, spec.MaxLen, padTypeRight, "\x00")
+	}
+
+	return e.createElement(data, spec.MaxLen, padTypeRight, " ")
+}
+
 // getElement retrieving element data based on a specific field from the elements map
 func (e *ElementsData) getElement(field int) (data string, exist bool) {
 	e.mu.RLock()
@@ -73,35 +91,42 @@ type Iso8583Data struct {
 	bitmapMu   sync.RWMutex
 	bitmapType bitmapTypeData
 	Spec       SpecData
+	Header     Header
 	Mti        MtiData
 	Bitmap     []int
 	BitmapSize int
 	Elements   ElementsData
+
+	contentTypeWarnings bool
+	collectAllErrors    bool
+	referenceTime       time.Time
+
+	// versionSpecs holds the data specification of every MTI version, see NewFromVersionSpecs
+	versionSpecs map[int]SpecData
+
+	warningsMu sync.Mutex
+	warnings   []error
 }
 
 // Create a new Iso8583Data object from a yaml specification file
-func New(filename string) (iso *Iso8583Data, err error) {
+func New(filename string, opts ...Option) (iso *Iso8583Data, err error) {
 	spec, err := SpecFromFile(filename)
 	if err != nil {
 		return iso, err
 	}
 
-	return createIsoObject(spec)
+	return createIsoObject(spec, opts...)
 }
 
 // Create a new Iso8583Data object from a predefined data specification
-func NewFromSpec(spec SpecData) (iso *Iso8583Data, err error) {
-	return createIsoObject(spec)
+func NewFromSpec(spec SpecData, opts ...Option) (iso *Iso8583Data, err error) {
+	return createIsoObject(spec, opts...)
 }
 
 // Private function that create a new Iso8583Data object from a predefined data specification.
-func createIsoObject(spec SpecData) (iso *Iso8583Data, err error) {
-	if len(spec.Fields) == 0 {
-		return iso, ErrEmptySpec
-	}
-
-	if !spec.hasAtLeastOneDataField() {
-		return iso, ErrSpecMinHasOneField
+func createIsoObject(spec SpecData, opts ...Option) (iso *Iso8583Data, err error) {
+	if err := checkSpec(spec); err != nil {
+		return iso, err
 	}
 
 	iso = &Iso8583Data{
@@ -113,11 +138,28 @@ func createIsoObject(spec SpecData) (iso *Iso8583Data, err error) {
 		Elements:   ElementsData{elements: make(map[int]string)},
 	}
 
+	for _, opt := range opts {
+		opt(iso)
+	}
+
 	return iso, nil
 }
 
+// Private function that checks a data specification can be used to create a new Iso8583Data object
+func checkSpec(spec SpecData) error {
+	if len(spec.Fields) == 0 {
+		return ErrEmptySpec
+	}
+
+	if !spec.hasAtLeastOneDataField() {
+		return ErrSpecMinHasOneField
+	}
+
+	return spec.validate()
+}
+
 // Reset clears all message state so the parser can be reused for a new message.
-// Spec is preserved; MTI, Bitmap, and all field elements are cleared.
+// Spec is preserved; Header, MTI, Bitmap, and all field elements are cleared.
 func (iso *Iso8583Data) Reset() {
 	iso.bitmapMu.Lock()
 	iso.bitmapType = bitmapTypePrimary
@@ -125,11 +167,38 @@ func (iso *Iso8583Data) Reset() {
 	iso.BitmapSize = bitmapSizePrimary
 	iso.bitmapMu.Unlock()
 
+	iso.Header = Header{}
 	iso.Mti = MtiData{}
 
 	iso.Elements.mu.Lock()
 	iso.Elements.elements = make(map[int]string)
+	iso.Elements.subelements = nil
 	iso.Elements.mu.Unlock()
+
+	iso.clearWarnings()
+}
+
+// Retrieves the warnings of the violations that were accepted because of the parser options,
+// the warnings are cleared on Reset and on every Unmarshal
+func (iso *Iso8583Data) Warnings() []error {
+	iso.warningsMu.Lock()
+	defer iso.warningsMu.Unlock()
+
+	warnings := make([]error, len(iso.warnings))
+	copy(warnings, iso.warnings)
+	return warnings
+}
+
+func (iso *Iso8583Data) addWarning(err error) {
+	iso.warningsMu.Lock()
+	iso.warnings = append(iso.warnings, err)
+	iso.warningsMu.Unlock()
+}
+
+func (iso *Iso8583Data) clearWarnings() {
+	iso.warningsMu.Lock()
+	iso.warnings = nil
+	iso.warningsMu.Unlock()
 }
 
 func (iso *Iso8583Data) configureNewBitmap() []int {
@@ -147,41 +216,62 @@ func (iso *Iso8583Data) configureNewBitmap() []int {
 	return iso.Bitmap[0:bitmapSizeTertiary]
 }
 
-// Set MTI data for the iso8583 message
+// Set MTI data for the iso8583 message.
+// When created with NewFromVersionSpecs the data specification of the MTI version is selected,
+// so the MTI should be set before the fields
 func (iso *Iso8583Data) AddMTI(mti string) error {
 	mtiData := MtiData{mti: mti}
 	if err := mtiData.validate(); err != nil {
 		return err
 	}
+
+	if err := iso.useVersionSpec(mti); err != nil {
+		return err
+	}
+
 	iso.Mti = mtiData
 	return nil
 }
 
 // Define specific field data by field number.
-// An error may occur if the field number entered is less than 2 or more than maxField (192)
+// An error may occur if the field number entered is less than 2 or more than maxField (192),
+// if the data length is outside MinLen and MaxLen of the field,
+// and if the data does not match the content type of the field.
+// The data of a field with subfields is decomposed, the subfields can be retrieved with GetSubfield
 func (iso *Iso8583Data) SetField(field int, data string) error {
 	if field < 2 || field > bitmapSizeTertiary {
-		return fmt.Errorf("expected field to be between %d and %d found %d instead", 2, bitmapSizeTertiary, field)
+		return newFieldError(field, PhaseValue, -1, ErrInvalidFieldNumber)
 	}
 
 	fieldSpec, ok := iso.Spec.Fields[field]
 	if !ok {
-		return fmt.Errorf("no field spec for field %d", field)
+		return newFieldError(field, PhaseValue, -1, ErrNoFieldSpec)
+	}
+
+	if len(data) > fieldSpec.MaxLen {
+		return newFieldError(field, PhaseValue, -1, fieldSpec.checkLength(field, len(data)))
 	}
 
-	maxLen := fieldSpec.MaxLen
-	dataLen := len(data)
+	data = iso.Elements.createFieldElement(fieldSpec, data)
+
+	if err := fieldSpec.checkLength(field, len(data)); err != nil {
+		return newFieldError(field, PhaseValue, -1, err)
+	}
 
-	if dataLen > maxLen {
-		return fmt.Errorf("failed to set field %d with max length %d but data length %d", field, maxLen, dataLen)
+	if err := validateContent(field, fieldSpec, data); err != nil {
+		if !iso.contentTypeWarnings {
+			return newFieldError(field, PhaseValue, -1, err)
+		}
+		iso.addWarning(newFieldError(field, PhaseValue, -1, err))
 	}
 
-	if strings.ToLower(fieldSpec.LenType) == "fixed" {
-		if fieldSpec.ContentType == "n" {
-			data = iso.Elements.createElement(data, maxLen, padTypeLeft, "0")
-		} else {
-			data = iso.Elements.createElement(data, maxLen, padTypeRight, " ")
+	var subelements map[int]string
+	if len(fieldSpec.Subfields) > 0 {
+		subs, err := decomposeSubfields(field, fieldSpec, data)
+		if err != nil {
+			return err
 		}
+		subelements = subs
 	}
 
 	iso.bitmapMu.Lock()
@@ -189,6 +279,7 @@ func (iso *Iso8583Data) SetField(field int, data string) error {
 	iso.bitmapMu.Unlock()
 
 	iso.Elements.setElement(field, data)
+	iso.Elements.setSubelements(field, subelements)
 	return nil
 }
 
@@ -224,16 +315,17 @@ func (iso *Iso8583Data) GetAllFieldKeySorted() []int {
 }
 
 // Perform ISO8583 data packaging based on fields and data that have been set returning bytes data iso message
-// Errors can occur if the data length in a particular field exceeds the field capacity in the configuration,
+// Errors can occur if the data length in a particular field is outside the field capacity in the configuration,
 // and if the field type depends on the length of the variable in the configuration
 // and is not part of the type (llvar, lllvar),
 // and if bitmap is invalid
+// Errors are returned as *FieldError describing the field and phase of the failure
 func (iso *Iso8583Data) Marshal() ([]byte, error) {
 	return iso.marshal()
 }
 
 // Perform ISO8583 data packaging based on fields and data that have been set returning text of iso message
-// Errors can occur if the data length in a particular field exceeds the field capacity in the configuration,
+// Errors can occur if the data length in a particular field is outside the field capacity in the configuration,
 // and if the field type depends on the length of the variable in the configuration
 // and is not part of the type (llvar, lllvar),
 // and if bitmap is invalid
@@ -263,28 +355,11 @@ func (iso *Iso8583Data) marshal() ([]byte, error) {
 
 		//In here element must be eksist
 		data, _ := iso.Elements.getElement(fieldNo)
-		var (
-			fieldSpec = iso.Spec.Fields[fieldNo]
-			maxLen    = fieldSpec.MaxLen
-			dataLen   = len(data)
-		)
-
-		if dataLen > maxLen {
-			return nil, fmt.Errorf("failed to marshal field %d with max length %d but data length %d", fieldNo, maxLen, dataLen)
-		}
-
-		if strings.ToLower(fieldSpec.LenType) == "fixed" {
-			bufData = append(bufData, data...)
-		} else {
-			lengthType, err := getVariableLengthFromString(fieldSpec.LenType)
-			if err != nil {
-				return nil, err
-			}
-
-			paddedLength := iso.Elements.createElement(strconv.Itoa(dataLen), lengthType, padTypeLeft, "0")
-			bufData = append(bufData, paddedLength...)
-			bufData = append(bufData, data...)
+		packed, err := packField(fieldNo, iso.Spec.Fields[fieldNo], data)
+		if err != nil {
+			return nil, err
 		}
+		bufData = append(bufData, packed...)
 
 		iso.bitmapMu.Lock()
 		iso.Bitmap[indexBit] = 1
@@ -298,20 +373,36 @@ func (iso *Iso8583Data) marshal() ([]byte, error) {
 	iso.BitmapSize = len(iso.Bitmap)
 	iso.bitmapMu.Unlock()
 
+	mtiEncoder, err := getEncoder(iso.Spec.Fields[0].Encoding)
+	if err != nil {
+		return nil, newFieldError(0, PhaseMTI, -1, err)
+	}
+
+	mtiBytes, err := mtiEncoder.Encode(iso.Mti.Get())
+	if err != nil {
+		return nil, newFieldError(0, PhaseMTI, -1, err)
+	}
+
+	headerBytes, err := iso.Spec.Header.encode(iso.Header)
+	if err != nil {
+		return nil, newFieldError(0, PhaseHeader, -1, err)
+	}
+
 	buf := make([]byte, 0, 512)
-	buf = append(buf, []byte(iso.Mti.Get())...)
+	buf = append(buf, headerBytes...)
+	buf = append(buf, mtiBytes...)
 
 	iso.bitmapMu.RLock()
 	bitmapSnapshot := make([]int, len(iso.Bitmap))
 	copy(bitmapSnapshot, iso.Bitmap)
 	iso.bitmapMu.RUnlock()
 
-	bitmapString, err := BitsIntArrayToHex(iso.Bitmap)
+	bitmapBytes, err := encodeBitmap(bitmapSnapshot, iso.Spec.BitmapEncoding)
 	if err != nil {
-		return nil, err
+		return nil, newFieldError(1, PhaseBitmap, -1, err)
 	}
 
-	buf = append(buf, []byte(bitmapString)...)
+	buf = append(buf, bitmapBytes...)
 	buf = append(buf, bufData...)
 
 	return buf, nil
@@ -319,39 +410,71 @@ func (iso *Iso8583Data) marshal() ([]byte, error) {
 
 // Perform ISO8583 data parsing according to predetermined specifications
 // form the data sent is in the form of a byte array
+// Errors are returned as *FieldError describing the field, phase and byte offset of the failure.
+// When created with NewFromVersionSpecs the data specification of the MTI version is used to parse the message
 func (iso *Iso8583Data) Unmarshal(bytesIso []byte) error {
-	if len(bytesIso) < 20 {
-		return ErrIsoMessageTooShort
+	header, headerLen, err := iso.Spec.Header.decode(bytesIso)
+	if errors.Is(err, ErrNotEnoughData) {
+		return &FieldError{Field: 0, Phase: PhaseHeader, Offset: 0, Expected: iso.Spec.Header.length(), Actual: len(bytesIso), Err: ErrIsoMessageTooShort}
+	}
+	if err != nil {
+		return newFieldError(0, PhaseHeader, 0, err)
 	}
 
-	specs := iso.Spec
-	mti := string(bytesIso[:MTILength])
+	mtiEncoder, err := getEncoder(iso.Spec.Fields[0].Encoding)
+	if err != nil {
+		return newFieldError(0, PhaseMTI, headerLen, err)
+	}
+
+	mti, read, err := mtiEncoder.Decode(bytesIso[headerLen:], MTILength)
+	if err != nil {
+		return &FieldError{Field: 0, Phase: PhaseMTI, Offset: headerLen, Expected: headerLen + MTILength, Actual: len(bytesIso), Err: ErrIsoMessageTooShort}
+	}
+	offset := headerLen + read
 
 	mtiData, _ := extractMti(mti)
 	if err := mtiData.validate(); err != nil {
-		return err
+		return newFieldError(0, PhaseMTI, headerLen, err)
 	}
 
-	iso.Mti = mtiData
+	if err := iso.useVersionSpec(mtiData.Get()); err != nil {
+		return newFieldError(0, PhaseMTI, headerLen, err)
+	}
+	specs := iso.Spec
+
+	bitmapLen, err := bitmapBlockLength(specs.BitmapEncoding)
+	if err != nil {
+		return newFieldError(1, PhaseBitmap, 0, err)
+	}
 
-	bitmapHex := bytesIso[MTILength : MTILength+BitmapLength]
-	bitmap := make([]byte, 8)
+	if len(bytesIso) < offset+bitmapLen {
+		return &FieldError{Field: 0, Phase: PhaseMTI, Offset: headerLen, Expected: offset + bitmapLen, Actual: len(bytesIso), Err: ErrIsoMessageTooShort}
+	}
 
-	if _, err := hex.Decode(bitmap, bitmapHex); err != nil {
-		return err
+	iso.Header = header
+	iso.Mti = mtiData
+	iso.bitmapType = bitmapTypePrimary
+	iso.Bitmap = make([]int, bitmapSizeTertiary)
+	iso.clearWarnings()
+
+	bitmap, read, err := decodeBitmapBlock(bytesIso[offset:], specs.BitmapEncoding)
+	if err != nil {
+		return newFieldError(1, PhaseBitmap, offset, err)
 	}
+	offset += read
 
 	bitmapSize := bitmapSizePrimary
 	if bitmap[0]&0x80 != 0 {
 		bitmapSize = bitmapSizeSecondary
-		bitmap = append(bitmap, make([]byte, 8)...)
-		if len(bytesIso) < 36 {
-			return ErrDataToShortSecondaryBitmap
+		secondBitmap, read, err := decodeBitmapBlock(bytesIso[offset:], specs.BitmapEncoding)
+		if errors.Is(err, ErrNotEnoughData) {
+			return &FieldError{Field: 1, Phase: PhaseBitmap, Offset: offset, Expected: bitmapLen, Actual: len(bytesIso) - offset, Err: ErrDataToShortSecondaryBitmap}
 		}
-
-		if _, err := hex.Decode(bitmap[8:], bytesIso[20:36]); err != nil {
-			return err
+		if err != nil {
+			return newFieldError(1, PhaseBitmap, offset, err)
 		}
+		bitmap = append(bitmap, secondBitmap...)
+		offset += read
 
 		iso.Bitmap[0] = 1
 		iso.bitmapType = bitmapTypeSecondary
@@ -359,28 +482,25 @@ func (iso *Iso8583Data) Unmarshal(bytesIso []byte) error {
 		//Cek tertiary bitmap
 		if bitmap[8]&0x80 != 0 {
 			bitmapSize = bitmapSizeTertiary
-			bitmap = append(bitmap, make([]byte, 8)...)
-			if len(bytesIso) < 52 {
-				return ErrDataToShortTertiaryBitmap
+			thirdBitmap, read, err := decodeBitmapBlock(bytesIso[offset:], specs.BitmapEncoding)
+			if errors.Is(err, ErrNotEnoughData) {
+				return &FieldError{Field: 1, Phase: PhaseBitmap, Offset: offset, Expected: bitmapLen, Actual: len(bytesIso) - offset, Err: ErrDataToShortTertiaryBitmap}
 			}
-
-			if _, err := hex.Decode(bitmap[16:], bytesIso[36:52]); err != nil {
-				return err
+			if err != nil {
+				return newFieldError(1, PhaseBitmap, offset, err)
 			}
+			bitmap = append(bitmap, thirdBitmap...)
+			offset += read
 
 			iso.Bitmap[64] = 1
 			iso.bitmapType = bitmapTypeTertiary
-			bytesIso = bytesIso[52:]
-		} else {
-			bytesIso = bytesIso[36:]
 		}
-	} else {
-		bytesIso = bytesIso[20:]
 	}
 
 	iso.BitmapSize = bitmapSize
 
-	pos := 0
+	var errs ValidationErrors
+	pos := offset
 	for i := 2; i <= bitmapSize; i++ {
 		if i == 65 {
 			continue
@@ -392,46 +512,31 @@ func (iso *Iso8583Data) Unmarshal(bytesIso []byte) error {
 		if bitmap[bytePos]&(1<<bitPos) != 0 {
 			spec, ok := specs.Fields[i]
 			if !ok {
-				return fmt.Errorf("no field spec for field %d", i)
+				return iso.unmarshalError(errs, newFieldError(i, PhaseBitmap, pos, ErrNoFieldSpec))
 			}
 
-			var fieldLen int
-			switch strings.ToLower(spec.LenType) {
-			case "fixed":
-				fieldLen = spec.MaxLen
-			case "llvar":
-				if pos+2 > len(bytesIso) {
-					return fmt.Errorf("field %d: LLVAR prefix too short", i)
-				}
-
-				n, err := strconv.Atoi(string(bytesIso[pos : pos+2]))
-				if err != nil {
-					return fmt.Errorf("field %d: LLVAR prefix is not an integer", i)
-				}
+			value, prefix, read, err := unpackField(i, spec, bytesIso[pos:], pos)
+			if err != nil {
+				return iso.unmarshalError(errs, err)
+			}
 
-				fieldLen = n
-				pos += 2
-			case "lllvar":
-				if pos+3 > len(bytesIso) {
-					return fmt.Errorf("field %d: LLLVAR prefix too short", i)
+			if err := iso.SetField(i, value); err != nil {
+				// The value is rejected, so the error is at the start of the value like the value errors of unpackField
+				var fieldErr *FieldError
+				if errors.As(err, &fieldErr) {
+					fieldErr.Offset = pos + prefix
 				}
-
-				n, err := strconv.Atoi(string(bytesIso[pos : pos+3]))
-				if err != nil {
-					return fmt.Errorf("field %d: LLLVAR prefix is not an integer", i)
+				if !iso.collectAllErrors {
+					return err
 				}
 
-				fieldLen = n
-				pos += 3
-			}
-
-			if pos+fieldLen > len(bytesIso) {
-				return fmt.Errorf("field %d: value too short", i)
+				// Keep the invalid value, the field boundary is still known so parsing can continue
+				errs = append(errs, err)
+				iso.Elements.setElement(i, value)
 			}
 
 			iso.Bitmap[i-1] = 1
-			iso.SetField(i, string(bytesIso[pos:pos+fieldLen]))
-			pos += fieldLen
+			pos += read
 		}
 	}
 
@@ -439,122 +544,110 @@ func (iso *Iso8583Data) Unmarshal(bytesIso []byte) error {
 	_ = copy(bitNew, iso.Bitmap[0:bitmapSize])
 	iso.Bitmap = bitNew
 
+	if iso.collectAllErrors {
+		errs = append(errs, iso.validateMandatory()...)
+		if len(errs) > 0 {
+			return errs
+		}
+	}
+
 	return nil
 }
 
-// Perform ISO8583 data parsing according to predetermined specifications
-// form the data sent is in the form of string
-func (iso *Iso8583Data) UnmarshalString(isoMessage string) error {
-	if len(isoMessage) < 20 {
-		return ErrIsoMessageTooShort
+// Return the error that stops unmarshal, in collect all errors mode it is returned with the violations found before
+func (iso *Iso8583Data) unmarshalError(errs ValidationErrors, err error) error {
+	if !iso.collectAllErrors {
+		return err
 	}
 
-	specs := iso.Spec
-	mti := string(isoMessage[:4])
+	return append(errs, err)
+}
 
-	mtiData, _ := extractMti(mti)
-	if err := mtiData.validate(); err != nil {
-		return err
+// Pack a field value into wire bytes, prefixed with the length for variable length fields
+func packField(field int, spec FieldSpec, data string) ([]byte, error) {
+	if err := spec.checkLength(field, len(data)); err != nil {
+		return nil, newFieldError(field, PhaseValue, -1, err)
 	}
 
-	iso.Mti = mtiData
-	iso.bitmapType = bitmapTypePrimary
-
-	bitmapHex := isoMessage[4:20]
-	bitmap, err := HexToBitsString(bitmapHex)
+	encoder, err := getEncoder(spec.Encoding)
 	if err != nil {
-		return err
+		return nil, newFieldError(field, PhaseValue, -1, err)
 	}
 
-	if bitmap[0] == '1' {
-		if len(isoMessage) < 36 {
-			return ErrDataToShortSecondaryBitmap
-		}
-
-		secondHex := isoMessage[20:36]
-		secondBitmap, err := HexToBitsString(secondHex)
-		if err != nil {
-			return err
-		}
-		bitmap += secondBitmap
-		iso.bitmapType = bitmapTypeSecondary
+	encoded, err := encoder.Encode(data)
+	if err != nil {
+		return nil, newFieldError(field, PhaseValue, -1, err)
+	}
 
-		iso.Bitmap[0] = 1
-		isoMessage = isoMessage[36:]
+	if strings.ToLower(spec.LenType) == "fixed" {
+		return encoded, nil
+	}
 
-		if bitmap[64] == '1' {
-			if len(isoMessage) < 16 {
-				return ErrDataToShortTertiaryBitmap
-			}
+	digits, err := getVariableLengthFromString(spec.LenType)
+	if err != nil {
+		return nil, newFieldError(field, PhasePrefix, -1, err)
+	}
 
-			thirdHex := isoMessage[0:16]
-			thirdBitmap, err := HexToBitsString(thirdHex)
-			if err != nil {
-				return err
-			}
-			bitmap += thirdBitmap
-			iso.bitmapType = bitmapTypeTertiary
+	prefixer, err := getPrefixer(spec.LenEncoding)
+	if err != nil {
+		return nil, newFieldError(field, PhasePrefix, -1, err)
+	}
 
-			iso.Bitmap[64] = 1
-			isoMessage = isoMessage[16:]
-		}
-	} else {
-		isoMessage = isoMessage[20:]
+	prefix, err := prefixer.EncodeLength(len(data), digits)
+	if err != nil {
+		return nil, newFieldError(field, PhasePrefix, -1, err)
 	}
 
-	pos := 0
-	for i, c := range bitmap {
-		bit := i + 1
-		if c != '1' || i == 0 || i == 64 {
-			continue
-		}
+	return append(prefix, encoded...), nil
+}
 
-		spec, ok := specs.Fields[bit]
-		if !ok {
-			return fmt.Errorf("no field spec for field %d", i)
+// Unpack a field value from the beginning of data returning the value with the number of bytes of the length prefix
+// and the number of bytes read, offset is the position of data in the whole message, used for error reporting
+func unpackField(field int, spec FieldSpec, data []byte, offset int) (value string, prefix int, read int, err error) {
+	fieldLen := spec.MaxLen
+	if strings.ToLower(spec.LenType) != "fixed" {
+		fieldLen, read, err = unpackLength(spec, data)
+		if errors.Is(err, ErrNotEnoughData) {
+			return "", 0, 0, &FieldError{Field: field, Phase: PhasePrefix, Offset: offset, Actual: len(data), Err: ErrPrefixTooShort}
 		}
+		if err != nil {
+			return "", 0, 0, newFieldError(field, PhasePrefix, offset, fmt.Errorf("%w: %w", ErrInvalidPrefix, err))
+		}
+	}
 
-		var fieldLen int
-		switch strings.ToLower(spec.LenType) {
-		case "fixed":
-			fieldLen = spec.MaxLen
-		case "llvar":
-			if pos+2 > len(isoMessage) {
-				return fmt.Errorf("field %d: LLVAR prefix too short", i)
-			}
-
-			n, err := strconv.Atoi(string(isoMessage[pos : pos+2]))
-			if err != nil {
-				return fmt.Errorf("field %d: LLVAR prefix is not an integer", i)
-			}
-
-			fieldLen = n
-			pos += 2
-		case "lllvar":
-			if pos+3 > len(isoMessage) {
-				return fmt.Errorf("field %d: LLLVAR prefix too short", i)
-			}
+	encoder, err := getEncoder(spec.Encoding)
+	if err != nil {
+		return "", 0, 0, newFieldError(field, PhaseValue, offset+read, err)
+	}
 
-			n, err := strconv.Atoi(string(isoMessage[pos : pos+3]))
-			if err != nil {
-				return fmt.Errorf("field %d: LLLVAR prefix is not an integer", i)
-			}
+	value, n, err := encoder.Decode(data[read:], fieldLen)
+	if errors.Is(err, ErrNotEnoughData) {
+		return "", 0, 0, &FieldError{Field: field, Phase: PhaseValue, Offset: offset + read, Expected: fieldLen, Actual: len(data) - read, Err: ErrValueTooShort}
+	}
+	if err != nil {
+		return "", 0, 0, newFieldError(field, PhaseValue, offset+read, err)
+	}
 
-			fieldLen = n
-			pos += 3
-		}
+	return value, read, read + n, nil
+}
 
-		if pos+fieldLen > len(isoMessage) {
-			return fmt.Errorf("field %d: value too short", i)
-		}
+// Read the length prefix of a variable length field from the beginning of data
+func unpackLength(spec FieldSpec, data []byte) (length int, read int, err error) {
+	digits, err := getVariableLengthFromString(spec.LenType)
+	if err != nil {
+		return 0, 0, err
+	}
 
-		iso.Bitmap[bit-1] = 1
-		iso.SetField(bit, string(isoMessage[pos:pos+fieldLen]))
-		pos += fieldLen
+	prefixer, err := getPrefixer(spec.LenEncoding)
+	if err != nil {
+		return 0, 0, err
 	}
 
-	iso.Bitmap = iso.configureNewBitmap()
-	iso.BitmapSize = len(iso.Bitmap)
+	return prefixer.DecodeLength(data, digits)
+}
 
-	return nil
+// Perform ISO8583 data parsing according to predetermined specifications
+// form the data sent is in the form of string
+func (iso *Iso8583Data) UnmarshalString(isoMessage string) error {
+	return iso.Unmarshal([]byte(isoMessage))
 }
@@ -1,6 +1,8 @@
 package iso8583parser
 
 import (
+	"errors"
+	"strings"
 	"sync"
 	"testing"
 
@@ -11,7 +13,7 @@ import (
 var (
 	bitArray         = []int{1, 0, 1, 1, 1, 1, 1, 1, 0, 0, 1, 1, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 1, 1, 1, 1, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
 	bitArrayTertiary = []int{1, 0, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
-	msgiso           = "2200bf38404109e30000000000001300000010070000000000150000000000000500000000000607111702150000000823edfr20230707110014260232dhfte4736fge40 41      42             43                                      0031470051234506123456101234567890006654321"
+	msgiso           = "2200bf38404109e30000000000001300000010070000000000150000000000000500000000000607111702150000000823456720230707110014260232dhfte4736fge40 41      42             43                                      0031470051234506123456101234567890006654321"
 	msgisoTertiary   = "2200b8000000000000008000000013000000c000000000000000100700000000001500000000000005061234561012345678900066543210000000500000005"
 )
 
@@ -23,7 +25,7 @@ func setDataIso(isoParser *Iso8583Data) {
 	isoParser.SetField(6, "6")
 	isoParser.SetField(7, "0711170215")
 	isoParser.SetField(8, "8")
-	isoParser.SetField(11, "23edfr")
+	isoParser.SetField(11, "234567")
 	isoParser.SetField(12, "202307")
 	isoParser.SetField(13, "0711")
 	isoParser.SetField(18, "0014")
@@ -96,7 +98,7 @@ func TestMarshalRace(t *testing.T) {
 		6:   "6",
 		7:   "0711170215",
 		8:   "8",
-		11:  "23edfr",
+		11:  "234567",
 		12:  "202307",
 		13:  "0711",
 		47:  "147",
@@ -235,3 +237,193 @@ func TestUnmarshalTertiary(t *testing.T) {
 	require.Equal(t, "123456", bit100, "Expected Bit100 to be equal")
 	require.Equal(t, "00000005", bit129, "Expected Bit129 to be equal")
 }
+
+func TestMarshalLongVariableLength(t *testing.T) {
+	specData := SpecData{
+		Fields: map[int]FieldSpec{
+			3:  {ContentType: "n", LenType: "fixed", MaxLen: 6},
+			55: {ContentType: "ans", LenType: "llllvar", MaxLen: 9999},
+			62: {ContentType: "ans", LenType: "lllllvar", MaxLen: 99999},
+			63: {ContentType: "ans", LenType: "llvar", MaxLen: 99},
+		},
+	}
+
+	isoParser, err := NewFromSpec(specData)
+	require.Nil(t, err, "Error should be nil")
+
+	bit55 := strings.Repeat("E", 1200)
+	bit62 := strings.Repeat("T", 12000)
+	isoParser.AddMTI("0200")
+	isoParser.SetField(3, "000000")
+	isoParser.SetField(55, bit55)
+	isoParser.SetField(62, bit62)
+	isoParser.SetField(63, "END")
+
+	isoMsg, err := isoParser.MarshalString()
+	require.Nil(t, err, "Error should be nil")
+	require.Equal(t, "02002000000000000206000000"+"1200"+bit55+"12000"+bit62+"03END", isoMsg, "Expected iso message to be equal")
+
+	unpacked, err := NewFromSpec(specData)
+	require.Nil(t, err, "Error should be nil")
+
+	err = unpacked.UnmarshalString(isoMsg)
+	require.Nil(t, err, "Error should be nil")
+
+	field55, _ := unpacked.GetField(55)
+	field62, _ := unpacked.GetField(62)
+	field63, _ := unpacked.GetField(63)
+	require.Equal(t, bit55, field55, "Expected Bit55 to be equal")
+	require.Equal(t, bit62, field62, "Expected Bit62 to be equal")
+	require.Equal(t, "END", field63, "Expected Bit63 to be equal")
+}
+
+func TestInvalidSpecRejected(t *testing.T) {
+	t.Run("Unknown LenType", func(t *testing.T) {
+		_, err := NewFromSpec(SpecData{Fields: map[int]FieldSpec{48: {ContentType: "ans", LenType: "lvar", MaxLen: 9}}})
+		assert.ErrorIs(t, err, ErrInvalidLenType)
+	})
+
+	t.Run("MaxLen exceeds prefix", func(t *testing.T) {
+		_, err := NewFromSpec(SpecData{Fields: map[int]FieldSpec{48: {ContentType: "ans", LenType: "llvar", MaxLen: 100}}})
+		assert.NotNil(t, err, "Expected error max length")
+	})
+
+	t.Run("Unknown encoding", func(t *testing.T) {
+		_, err := NewFromSpec(SpecData{Fields: map[int]FieldSpec{3: {ContentType: "n", LenType: "fixed", MaxLen: 6, Encoding: "utf8"}}})
+		assert.NotNil(t, err, "Expected error encoding")
+	})
+}
+
+func TestFieldLength(t *testing.T) {
+	t.Run("SetField below MinLen", func(t *testing.T) {
+		isoParser, err := NewFromSpec(SpecData1987)
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.SetField(2, "4111")
+		var lengthErr *LengthError
+		require.True(t, errors.As(err, &lengthErr), "Expected length error")
+		assert.ErrorIs(t, err, ErrFieldTooShort)
+		assert.Equal(t, LengthError{Field: 2, MinLen: 12, MaxLen: 19, Length: 4, Err: ErrFieldTooShort}, *lengthErr)
+	})
+
+	t.Run("SetField above MaxLen", func(t *testing.T) {
+		isoParser, err := NewFromSpec(SpecData1987)
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.SetField(3, "1234567")
+		assert.ErrorIs(t, err, ErrFieldTooLong)
+	})
+
+	t.Run("Marshal below MinLen", func(t *testing.T) {
+		isoParser, err := NewFromSpec(SpecData1987)
+		require.Nil(t, err, "Error should be nil")
+
+		isoParser.AddMTI("0200")
+		isoParser.Elements.setElement(2, "4111")
+
+		_, err = isoParser.Marshal()
+		assert.ErrorIs(t, err, ErrFieldTooShort)
+	})
+
+	t.Run("Unmarshal below MinLen", func(t *testing.T) {
+		isoParser, err := NewFromSpec(SpecData1987)
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.UnmarshalString("0200" + "4000000000000000" + "044111")
+		var lengthErr *LengthError
+		require.True(t, errors.As(err, &lengthErr), "Expected length error")
+		assert.Equal(t, 2, lengthErr.Field, "Expected field to be equal")
+		assert.Equal(t, 4, lengthErr.Length, "Expected length to be equal")
+	})
+}
+
+func TestUnmarshalFieldError(t *testing.T) {
+	tests := []struct {
+		name     string
+		isoMsg   string
+		expected FieldError
+	}{
+		{
+			name:     "Message too short",
+			isoMsg:   "0200200000",
+			expected: FieldError{Field: 0, Phase: PhaseMTI, Offset: 0, Expected: 20, Actual: 10, Err: ErrIsoMessageTooShort},
+		},
+		{
+			name:     "Invalid MTI",
+			isoMsg:   "02X02000000000000000",
+			expected: FieldError{Field: 0, Phase: PhaseMTI, Offset: 0, Err: ErrInvalidMtiInteger},
+		},
+		{
+			name:     "Secondary bitmap too short",
+			isoMsg:   "0200A0000000000000000000",
+			expected: FieldError{Field: 1, Phase: PhaseBitmap, Offset: 20, Expected: 16, Actual: 4, Err: ErrDataToShortSecondaryBitmap},
+		},
+		{
+			name:     "Prefix too short",
+			isoMsg:   "02004000000000000000" + "1",
+			expected: FieldError{Field: 2, Phase: PhasePrefix, Offset: 20, Actual: 1, Err: ErrPrefixTooShort},
+		},
+		{
+			name:     "Value too short",
+			isoMsg:   "02006000000000000000" + "12411111111111" + "30",
+			expected: FieldError{Field: 3, Phase: PhaseValue, Offset: 34, Expected: 6, Actual: 2, Err: ErrValueTooShort},
+		},
+		{
+			name:     "Value too long",
+			isoMsg:   "02004000000000000000" + "2041111111111111111111",
+			expected: FieldError{Field: 2, Phase: PhaseValue, Offset: 22, Expected: 19, Actual: 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isoParser, err := NewFromSpec(SpecData1987)
+			require.Nil(t, err, "Error should be nil")
+
+			err = isoParser.UnmarshalString(tt.isoMsg)
+			var fieldErr *FieldError
+			require.True(t, errors.As(err, &fieldErr), "Expected field error, got %v", err)
+			assert.Equal(t, tt.expected.Field, fieldErr.Field, "Expected field to be equal")
+			assert.Equal(t, tt.expected.Phase, fieldErr.Phase, "Expected phase to be equal")
+			assert.Equal(t, tt.expected.Offset, fieldErr.Offset, "Expected offset to be equal")
+			assert.Equal(t, tt.expected.Expected, fieldErr.Expected, "Expected expected length to be equal")
+			assert.Equal(t, tt.expected.Actual, fieldErr.Actual, "Expected actual length to be equal")
+			if tt.expected.Err != nil {
+				assert.ErrorIs(t, err, tt.expected.Err)
+			}
+		})
+	}
+
+	t.Run("Invalid prefix", func(t *testing.T) {
+		isoParser, err := NewFromSpec(SpecData1987)
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.UnmarshalString("02004000000000000000" + "1X4111111111111")
+		assert.ErrorIs(t, err, ErrInvalidPrefix)
+	})
+
+	t.Run("No field spec", func(t *testing.T) {
+		isoParser, err := NewFromSpec(SpecData{Fields: map[int]FieldSpec{3: {ContentType: "n", LenType: "fixed", MaxLen: 6}}})
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.UnmarshalString("02004000000000000000" + "124111111111111")
+		var fieldErr *FieldError
+		require.True(t, errors.As(err, &fieldErr), "Expected field error")
+		assert.Equal(t, 2, fieldErr.Field, "Expected field to be equal")
+		assert.ErrorIs(t, err, ErrNoFieldSpec)
+	})
+}
+
+func TestSetFieldFieldError(t *testing.T) {
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+
+	err = isoParser.SetField(200, "1")
+	assert.ErrorIs(t, err, ErrInvalidFieldNumber)
+
+	err = isoParser.SetField(2, "4111")
+	var fieldErr *FieldError
+	require.True(t, errors.As(err, &fieldErr), "Expected field error")
+	assert.Equal(t, FieldError{Field: 2, Phase: PhaseValue, Offset: -1, Expected: 12, Actual: 4, Err: fieldErr.Err}, *fieldErr)
+	assert.ErrorIs(t, err, ErrFieldTooShort)
+}
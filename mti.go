@@ -1,7 +1,9 @@
 package iso8583parser
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 )
 
 const MTILength = 4
@@ -10,13 +12,136 @@ type MtiData struct {
 	mti string
 }
 
+// MtiVersion is the first MTI digit, the ISO 8583 version of the message
+type MtiVersion int
+
+const (
+	MtiVersion1987     MtiVersion = 0
+	MtiVersion1993     MtiVersion = 1
+	MtiVersion2003     MtiVersion = 2
+	MtiVersionNational MtiVersion = 8
+	MtiVersionPrivate  MtiVersion = 9
+)
+
+// MtiClass is the second MTI digit, the overall purpose of the message
+type MtiClass int
+
+const (
+	MtiClassAuthorization     MtiClass = 1
+	MtiClassFinancial         MtiClass = 2
+	MtiClassFileAction        MtiClass = 3
+	MtiClassReversal          MtiClass = 4 // reversal and chargeback
+	MtiClassReconciliation    MtiClass = 5
+	MtiClassAdministrative    MtiClass = 6
+	MtiClassFeeCollection     MtiClass = 7
+	MtiClassNetworkManagement MtiClass = 8
+)
+
+// MtiFunction is the third MTI digit, how the message must be processed
+type MtiFunction int
+
+const (
+	MtiFunctionRequest         MtiFunction = 0
+	MtiFunctionRequestResponse MtiFunction = 1
+	MtiFunctionAdvice          MtiFunction = 2
+	MtiFunctionAdviceResponse  MtiFunction = 3
+	MtiFunctionNotification    MtiFunction = 4
+	MtiFunctionNotificationAck MtiFunction = 5
+	MtiFunctionInstruction     MtiFunction = 6
+	MtiFunctionInstructionAck  MtiFunction = 7
+)
+
+// MtiOrigin is the fourth MTI digit, who began the message and whether it is a repeat
+type MtiOrigin int
+
+const (
+	MtiOriginAcquirer       MtiOrigin = 0
+	MtiOriginAcquirerRepeat MtiOrigin = 1
+	MtiOriginIssuer         MtiOrigin = 2
+	MtiOriginIssuerRepeat   MtiOrigin = 3
+	MtiOriginOther          MtiOrigin = 4
+	MtiOriginOtherRepeat    MtiOrigin = 5
+)
+
+// Digits of the class, function and origin that are defined in every ISO 8583 version,
+// the national and private versions may use every digit
+var mtiVersionDigits = map[MtiVersion]struct {
+	classes   string
+	functions string
+	origins   string
+}{
+	MtiVersion1987: {classes: "1234568", functions: "01234", origins: "0123"},
+	MtiVersion1993: {classes: "12345678", functions: "012345", origins: "012345"},
+	MtiVersion2003: {classes: "12345678", functions: "01234567", origins: "012345"},
+}
+
 // Retrieving MTI code
 func (m *MtiData) Get() string {
 	return m.mti
 }
 
+// Retrieving the version of the MTI, -1 when the MTI is not set
+func (m *MtiData) Version() MtiVersion {
+	return MtiVersion(m.digit(0))
+}
+
+// Retrieving the class of the MTI, -1 when the MTI is not set
+func (m *MtiData) Class() MtiClass {
+	return MtiClass(m.digit(1))
+}
+
+// Retrieving the function of the MTI, -1 when the MTI is not set
+func (m *MtiData) Function() MtiFunction {
+	return MtiFunction(m.digit(2))
+}
+
+// Retrieving the origin of the MTI, -1 when the MTI is not set
+func (m *MtiData) Origin() MtiOrigin {
+	return MtiOrigin(m.digit(3))
+}
+
+// Check whether the MTI is a request (e.g. 0200)
+func (m *MtiData) IsRequest() bool {
+	return m.Function() == MtiFunctionRequest
+}
+
+// Check whether the MTI is a response, the function of a response is odd (e.g. 0210, 0430, 0810)
+func (m *MtiData) IsResponse() bool {
+	return m.Function() >= 0 && m.Function()%2 == 1
+}
+
+// Check whether the MTI is an advice (e.g. 0220)
+func (m *MtiData) IsAdvice() bool {
+	return m.Function() == MtiFunctionAdvice
+}
+
+// Check whether the MTI is a repeat, the origin of a repeat is odd (e.g. 0201, 0421)
+func (m *MtiData) IsRepeat() bool {
+	return m.Origin() >= 0 && m.Origin()%2 == 1
+}
+
+// Check whether the MTI is a reversal or chargeback (e.g. 0400)
+func (m *MtiData) IsReversal() bool {
+	return m.Class() == MtiClassReversal
+}
+
+// Check whether the MTI is a network management message (e.g. 0800)
+func (m *MtiData) IsNetworkManagement() bool {
+	return m.Class() == MtiClassNetworkManagement
+}
+
+// Get the digit at the position of the MTI, -1 when the MTI is not set or not numeric
+func (m *MtiData) digit(pos int) int {
+	if len(m.mti) != MTILength || m.mti[pos] < '0' || m.mti[pos] > '9' {
+		return -1
+	}
+
+	return int(m.mti[pos] - '0')
+}
+
 // Private function to validate MTI code
-// Errors can occur if invalid length of MTI or MTI is not an integer value
+// Errors can occur if invalid length of MTI, MTI is not an integer value
+// or the class, function or origin is not defined in the version of the MTI
 func (m *MtiData) validate() error {
 	if len(m.mti) != 4 {
 		return ErrInvalidMtiLength
@@ -27,5 +152,107 @@ func (m *MtiData) validate() error {
 		return ErrInvalidMtiInteger
 	}
 
+	version := m.Version()
+	if version == MtiVersionNational || version == MtiVersionPrivate {
+		return nil
+	}
+
+	digits, ok := mtiVersionDigits[version]
+	if !ok {
+		return fmt.Errorf("%w: %s version %d is reserved", ErrUndefinedMti, m.mti, version)
+	}
+
+	for _, position := range []struct {
+		name    string
+		digit   byte
+		defined string
+	}{{"class", m.mti[1], digits.classes}, {"function", m.mti[2], digits.functions}, {"origin", m.mti[3], digits.origins}} {
+		if !strings.ContainsRune(position.defined, rune(position.digit)) {
+			return fmt.Errorf("%w: %s %s %c is not defined in version %s", ErrUndefinedMti, m.mti, position.name, position.digit, version)
+		}
+	}
+
 	return nil
 }
+
+func (v MtiVersion) String() string {
+	switch v {
+	case MtiVersion1987:
+		return "1987"
+	case MtiVersion1993:
+		return "1993"
+	case MtiVersion2003:
+		return "2003"
+	case MtiVersionNational:
+		return "national"
+	case MtiVersionPrivate:
+		return "private"
+	}
+
+	return fmt.Sprintf("MtiVersion(%d)", int(v))
+}
+
+func (c MtiClass) String() string {
+	switch c {
+	case MtiClassAuthorization:
+		return "authorization"
+	case MtiClassFinancial:
+		return "financial"
+	case MtiClassFileAction:
+		return "file-action"
+	case MtiClassReversal:
+		return "reversal"
+	case MtiClassReconciliation:
+		return "reconciliation"
+	case MtiClassAdministrative:
+		return "administrative"
+	case MtiClassFeeCollection:
+		return "fee-collection"
+	case MtiClassNetworkManagement:
+		return "network-management"
+	}
+
+	return fmt.Sprintf("MtiClass(%d)", int(c))
+}
+
+func (f MtiFunction) String() string {
+	switch f {
+	case MtiFunctionRequest:
+		return "request"
+	case MtiFunctionRequestResponse:
+		return "request-response"
+	case MtiFunctionAdvice:
+		return "advice"
+	case MtiFunctionAdviceResponse:
+		return "advice-response"
+	case MtiFunctionNotification:
+		return "notification"
+	case MtiFunctionNotificationAck:
+		return "notification-ack"
+	case MtiFunctionInstruction:
+		return "instruction"
+	case MtiFunctionInstructionAck:
+		return "instruction-ack"
+	}
+
+	return fmt.Sprintf("MtiFunction(%d)", int(f))
+}
+
+func (o MtiOrigin) String() string {
+	switch o {
+	case MtiOriginAcquirer:
+		return "acquirer"
+	case MtiOriginAcquirerRepeat:
+		return "acquirer-repeat"
+	case MtiOriginIssuer:
+		return "issuer"
+	case MtiOriginIssuerRepeat:
+		return "issuer-repeat"
+	case MtiOriginOther:
+		return "other"
+	case MtiOriginOtherRepeat:
+		return "other-repeat"
+	}
+
+	return fmt.Sprintf("MtiOrigin(%d)", int(o))
+}
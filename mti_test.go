@@ -0,0 +1,115 @@
+package iso8583parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMtiPositions(t *testing.T) {
+	tests := []struct {
+		mti      string
+		version  MtiVersion
+		class    MtiClass
+		function MtiFunction
+		origin   MtiOrigin
+	}{
+		{"0200", MtiVersion1987, MtiClassFinancial, MtiFunctionRequest, MtiOriginAcquirer},
+		{"0421", MtiVersion1987, MtiClassReversal, MtiFunctionAdvice, MtiOriginAcquirerRepeat},
+		{"1814", MtiVersion1993, MtiClassNetworkManagement, MtiFunctionRequestResponse, MtiOriginOther},
+		{"2763", MtiVersion2003, MtiClassFeeCollection, MtiFunctionInstruction, MtiOriginIssuerRepeat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mti, func(t *testing.T) {
+			mti := MtiData{mti: tt.mti}
+			assert.Equal(t, tt.version, mti.Version(), "Expected version to be equal")
+			assert.Equal(t, tt.class, mti.Class(), "Expected class to be equal")
+			assert.Equal(t, tt.function, mti.Function(), "Expected function to be equal")
+			assert.Equal(t, tt.origin, mti.Origin(), "Expected origin to be equal")
+		})
+	}
+
+	t.Run("Not set", func(t *testing.T) {
+		mti := MtiData{}
+		assert.Equal(t, MtiVersion(-1), mti.Version(), "Expected no version")
+		assert.False(t, mti.IsRequest(), "Expected no request")
+		assert.False(t, mti.IsResponse(), "Expected no response")
+	})
+
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, "2003", MtiVersion2003.String())
+		assert.Equal(t, "network-management", MtiClassNetworkManagement.String())
+		assert.Equal(t, "advice-response", MtiFunctionAdviceResponse.String())
+		assert.Equal(t, "issuer-repeat", MtiOriginIssuerRepeat.String())
+		assert.Equal(t, "MtiClass(9)", MtiClass(9).String())
+	})
+}
+
+func TestMtiPredicates(t *testing.T) {
+	tests := []struct {
+		mti      string
+		request  bool
+		response bool
+		advice   bool
+		repeat   bool
+		reversal bool
+		network  bool
+	}{
+		{"0200", true, false, false, false, false, false},
+		{"0210", false, true, false, false, false, false},
+		{"0221", false, false, true, true, false, false},
+		{"0430", false, true, false, false, true, false},
+		{"1420", false, false, true, false, true, false},
+		{"0800", true, false, false, false, false, true},
+		{"2813", false, true, false, true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mti, func(t *testing.T) {
+			mti := MtiData{mti: tt.mti}
+			assert.Equal(t, tt.request, mti.IsRequest(), "Expected IsRequest to be equal")
+			assert.Equal(t, tt.response, mti.IsResponse(), "Expected IsResponse to be equal")
+			assert.Equal(t, tt.advice, mti.IsAdvice(), "Expected IsAdvice to be equal")
+			assert.Equal(t, tt.repeat, mti.IsRepeat(), "Expected IsRepeat to be equal")
+			assert.Equal(t, tt.reversal, mti.IsReversal(), "Expected IsReversal to be equal")
+			assert.Equal(t, tt.network, mti.IsNetworkManagement(), "Expected IsNetworkManagement to be equal")
+		})
+	}
+}
+
+func TestMtiValidate(t *testing.T) {
+	valid := []string{"0100", "0200", "0421", "0800", "1304", "1644", "2200", "2765", "8999", "9070"}
+	for _, mti := range valid {
+		t.Run(mti, func(t *testing.T) {
+			assert.Nil(t, (&MtiData{mti: mti}).validate(), "Expected MTI to be valid")
+		})
+	}
+
+	invalid := []struct {
+		mti string
+		err error
+	}{
+		{"020", ErrInvalidMtiLength},
+		{"02A0", ErrInvalidMtiInteger},
+		{"0000", ErrUndefinedMti}, // class 0 is reserved
+		{"0700", ErrUndefinedMti}, // fee collection is not defined in 1987
+		{"0250", ErrUndefinedMti}, // notification acknowledgement is not defined in 1987
+		{"0204", ErrUndefinedMti}, // other origin is not defined in 1987
+		{"1260", ErrUndefinedMti}, // instruction is not defined in 1993
+		{"2900", ErrUndefinedMti}, // class 9 is reserved
+		{"2286", ErrUndefinedMti}, // origin 6 is reserved
+		{"3200", ErrUndefinedMti}, // version 3 is reserved
+	}
+	for _, tt := range invalid {
+		t.Run(tt.mti, func(t *testing.T) {
+			assert.ErrorIs(t, (&MtiData{mti: tt.mti}).validate(), tt.err)
+		})
+	}
+
+	t.Run("AddMTI", func(t *testing.T) {
+		isoParser, _ := NewFromSpec(SpecData1987)
+		assert.ErrorIs(t, isoParser.AddMTI("0700"), ErrUndefinedMti)
+		assert.Equal(t, "", isoParser.Mti.Get(), "Expected MTI not to be set")
+	})
+}
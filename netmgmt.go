@@ -0,0 +1,285 @@
+package iso8583parser
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Network management information codes of field 70
+const (
+	NetworkCodeSignOn      = "001"
+	NetworkCodeSignOff     = "002"
+	NetworkCodeKeyExchange = "161"
+	NetworkCodeEcho        = "301"
+)
+
+// MTI of the network management messages
+const (
+	MTINetworkRequest  = "0800"
+	MTINetworkResponse = "0810"
+)
+
+// LinkState is the state of the link with the host
+type LinkState int
+
+const (
+	LinkDown      LinkState = iota // the connection is closed or the last echo test failed
+	LinkConnected                  // the connection is open and not signed on
+	LinkSignedOn                   // the host accepted the sign on
+)
+
+func (s LinkState) String() string {
+	switch s {
+	case LinkDown:
+		return "down"
+	case LinkConnected:
+		return "connected"
+	case LinkSignedOn:
+		return "signed-on"
+	}
+
+	return fmt.Sprintf("LinkState(%d)", int(s))
+}
+
+// LinkEvent describes a change of the link state,
+// Code is the network management information code that caused the change and Err the failure that caused it
+type LinkEvent struct {
+	State LinkState
+	Code  string
+	Err   error
+}
+
+// NetworkManagement configures the network management messages of a Client
+type NetworkManagement struct {
+	// EchoInterval is the interval of the echo test (0800 with field 70 = 301), 0 disables the echo test
+	EchoInterval time.Duration
+
+	// SignOn makes the client sign on (field 70 = 001) when it is connected and again after a failed echo test,
+	// requests other than network management are rejected with ErrNotSignedOn until the host accepted the sign on
+	SignOn bool
+
+	// Timeout of every network management request, the send timeout of the client is used when it is 0
+	Timeout time.Duration
+
+	// KeyExchange handles a key exchange request of the host (field 70 = 161) and adds the key data to the response,
+	// the response has response code 96 when it returns an error. Without KeyExchange the request is answered with 00
+	KeyExchange func(request, response *Iso8583Data) error
+
+	// OnLinkEvent receives every change of the link state and every failed sign on,
+	// it may be called from more than one goroutine
+	OnLinkEvent func(event LinkEvent)
+}
+
+// WithNetworkManagement makes the client send echo tests and sign on automatically,
+// and answer the network management requests of the host
+func WithNetworkManagement(nm NetworkManagement) ClientOption {
+	return func(c *Client) {
+		c.network = &nm
+	}
+}
+
+// Retrieves the state of the link with the host
+func (c *Client) State() LinkState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return LinkDown
+	}
+	return c.state
+}
+
+// Check whether the link accepts requests other than network management,
+// it must be signed on when the network management requires a sign on
+func (c *Client) usable() bool {
+	switch c.State() {
+	case LinkSignedOn:
+		return true
+	case LinkConnected:
+		return c.network == nil || !c.network.SignOn
+	}
+
+	return false
+}
+
+// Change the link state and report the event when the state changed
+func (c *Client) setState(state LinkState, code string, err error) {
+	c.mu.Lock()
+	changed := c.state != state
+	c.state = state
+	c.mu.Unlock()
+
+	if changed && c.network != nil && c.network.OnLinkEvent != nil {
+		c.network.OnLinkEvent(LinkEvent{State: state, Code: code, Err: err})
+	}
+}
+
+// Echo sends an echo test to the host
+func (c *Client) Echo(ctx context.Context) error {
+	_, err := c.SendNetwork(ctx, NetworkCodeEcho, nil)
+	return err
+}
+
+// SignOn signs on to the host, the link state is LinkSignedOn when the host accepts it
+func (c *Client) SignOn(ctx context.Context) error {
+	if _, err := c.SendNetwork(ctx, NetworkCodeSignOn, nil); err != nil {
+		return err
+	}
+
+	c.setState(LinkSignedOn, NetworkCodeSignOn, nil)
+	return nil
+}
+
+// SignOff signs off from the host, the link state is LinkConnected afterwards
+func (c *Client) SignOff(ctx context.Context) error {
+	if _, err := c.SendNetwork(ctx, NetworkCodeSignOff, nil); err != nil {
+		return err
+	}
+
+	c.setState(LinkConnected, NetworkCodeSignOff, nil)
+	return nil
+}
+
+// KeyExchange sends a key exchange request with the key data fields and returns the response of the host
+func (c *Client) KeyExchange(ctx context.Context, fields map[int]string) (*Iso8583Data, error) {
+	return c.SendNetwork(ctx, NetworkCodeKeyExchange, fields)
+}
+
+// SendNetwork sends a network management request with the network management information code and extra fields,
+// the STAN and transmission date and time are set automatically.
+// An error may occur if the request fails or the host does not answer with response code 00
+func (c *Client) SendNetwork(ctx context.Context, code string, fields map[int]string) (*Iso8583Data, error) {
+	request, err := NewFromSpec(c.spec, c.messageOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddMTI(MTINetworkRequest)
+	if err := request.SetDateTime(7, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := request.SetField(11, c.nextSTAN()); err != nil {
+		return nil, err
+	}
+	if err := request.SetField(70, code); err != nil {
+		return nil, err
+	}
+	for field, value := range fields {
+		if err := request.SetField(field, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.network != nil && c.network.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.network.Timeout)
+		defer cancel()
+	}
+
+	response, err := c.Send(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if responseCode, _ := response.GetField(39); responseCode != "00" {
+		return response, fmt.Errorf("%w: network management %s response code %q", ErrNetworkRequestDeclined, code, responseCode)
+	}
+
+	return response, nil
+}
+
+// Generate the next STAN of the network management requests, from 000001 to 999999
+func (c *Client) nextSTAN() string {
+	return fmt.Sprintf("%06d", (c.stan.Add(1)-1)%999999+1)
+}
+
+// Sign on and send the echo tests until the connection is done
+func (c *Client) manageNetwork() {
+	nm := c.network
+
+	if nm.SignOn {
+		c.signOn()
+	}
+
+	if nm.EchoInterval <= 0 {
+		<-c.done
+		c.setState(LinkDown, "", c.Err())
+		return
+	}
+
+	ticker := time.NewTicker(nm.EchoInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			c.setState(LinkDown, "", c.Err())
+			return
+		case <-ticker.C:
+		}
+
+		if err := c.Echo(context.Background()); err != nil {
+			c.setState(LinkDown, NetworkCodeEcho, err)
+			continue
+		}
+
+		if c.State() == LinkDown {
+			c.setState(LinkConnected, NetworkCodeEcho, nil)
+		}
+		if nm.SignOn && c.State() != LinkSignedOn {
+			c.signOn()
+		}
+	}
+}
+
+// Sign on, a failed sign on is reported as event without changing the link state
+func (c *Client) signOn() {
+	if err := c.SignOn(context.Background()); err != nil && c.network.OnLinkEvent != nil {
+		c.network.OnLinkEvent(LinkEvent{State: c.State(), Code: NetworkCodeSignOn, Err: err})
+	}
+}
+
+// Answer a network management request of the host, the answer keeps the transmission date and time, STAN and code
+func (c *Client) answerNetwork(request *Iso8583Data) {
+	response, err := NewFromSpec(c.spec, c.messageOpts...)
+	if err != nil {
+		c.reportError(err)
+		return
+	}
+
+	response.Header = request.Header.Swap()
+	response.AddMTI(MTINetworkResponse)
+	for _, field := range []int{7, 11, 70} {
+		if value, err := request.GetField(field); err == nil {
+			response.SetField(field, value)
+		}
+	}
+
+	responseCode := "00"
+	code, _ := request.GetField(70)
+	switch code {
+	case NetworkCodeKeyExchange:
+		if c.network.KeyExchange != nil {
+			if err := c.network.KeyExchange(request, response); err != nil {
+				c.reportError(err)
+				responseCode = "96"
+			}
+		}
+	case NetworkCodeSignOff:
+		if c.State() == LinkSignedOn {
+			c.setState(LinkConnected, NetworkCodeSignOff, nil)
+		}
+	}
+	response.SetField(39, responseCode)
+
+	if err := c.Write(response); err != nil {
+		c.reportError(err)
+	}
+}
+
+func (c *Client) reportError(err error) {
+	if c.onError != nil {
+		c.onError(err)
+	}
+}
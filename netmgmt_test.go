@@ -0,0 +1,160 @@
+package iso8583parser
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Create the response of a network management request of the test host
+func testNetworkResponse(request *Iso8583Data, responseCode string) *Iso8583Data {
+	response := testResponse(request, responseCode)
+	bit70, _ := request.GetField(70)
+	response.SetField(70, bit70)
+	return response
+}
+
+func waitLinkEvent(t *testing.T, events chan LinkEvent, state LinkState) LinkEvent {
+	t.Helper()
+
+	for {
+		select {
+		case event := <-events:
+			if event.State == state {
+				return event
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected link state %s", state)
+		}
+	}
+}
+
+func TestNetworkManagementEcho(t *testing.T) {
+	clientConn, hostConn := net.Pipe()
+	framer := NewFramer(FrameHeaderBinary2)
+
+	var answerEcho atomic.Bool
+	answerEcho.Store(true)
+	var echoes atomic.Int32
+	runTestHost(t, hostConn, func(request *Iso8583Data) {
+		code, _ := request.GetField(70)
+		if code == NetworkCodeEcho {
+			echoes.Add(1)
+			if !answerEcho.Load() {
+				return
+			}
+		}
+		go framer.WriteMessage(hostConn, testNetworkResponse(request, "00"))
+	})
+
+	events := make(chan LinkEvent, 16)
+	client := NewClient(clientConn, SpecData1987, WithNetworkManagement(NetworkManagement{
+		EchoInterval: 20 * time.Millisecond,
+		SignOn:       true,
+		Timeout:      50 * time.Millisecond,
+		OnLinkEvent:  func(event LinkEvent) { events <- event },
+	}))
+
+	event := waitLinkEvent(t, events, LinkSignedOn)
+	assert.Equal(t, NetworkCodeSignOn, event.Code, "Expected sign on event")
+	require.Eventually(t, func() bool { return echoes.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	answerEcho.Store(false)
+	event = waitLinkEvent(t, events, LinkDown)
+	assert.Equal(t, NetworkCodeEcho, event.Code, "Expected echo test to fail")
+	assert.ErrorIs(t, event.Err, ErrSendTimeout)
+
+	answerEcho.Store(true)
+	waitLinkEvent(t, events, LinkSignedOn)
+
+	require.Nil(t, client.SignOff(context.Background()))
+	assert.Equal(t, LinkConnected, client.State(), "Expected link to be signed off")
+
+	require.Nil(t, client.Close())
+	waitLinkEvent(t, events, LinkDown)
+}
+
+func TestNetworkManagementSignOnDeclined(t *testing.T) {
+	clientConn, hostConn := net.Pipe()
+	framer := NewFramer(FrameHeaderBinary2)
+
+	runTestHost(t, hostConn, func(request *Iso8583Data) {
+		go framer.WriteMessage(hostConn, testNetworkResponse(request, "91"))
+	})
+
+	events := make(chan LinkEvent, 16)
+	client := NewClient(clientConn, SpecData1987, WithNetworkManagement(NetworkManagement{
+		SignOn:      true,
+		OnLinkEvent: func(event LinkEvent) { events <- event },
+	}))
+	defer client.Close()
+
+	event := waitLinkEvent(t, events, LinkConnected)
+	assert.Equal(t, NetworkCodeSignOn, event.Code, "Expected sign on event")
+	assert.ErrorIs(t, event.Err, ErrNetworkRequestDeclined)
+
+	_, err := client.Send(context.Background(), testRequest("0200", "000001"))
+	assert.ErrorIs(t, err, ErrNotSignedOn)
+}
+
+func TestNetworkManagementAnswer(t *testing.T) {
+	clientConn, hostConn := net.Pipe()
+	framer := NewFramer(FrameHeaderBinary2)
+
+	responses := make(chan *Iso8583Data, 1)
+	runTestHost(t, hostConn, func(response *Iso8583Data) {
+		responses <- response
+	})
+
+	client := NewClient(clientConn, SpecData1987, WithNetworkManagement(NetworkManagement{
+		KeyExchange: func(request, response *Iso8583Data) error {
+			if bit48, _ := request.GetField(48); bit48 != "KEY1" {
+				return errors.New("unknown key")
+			}
+			return response.SetField(48, "CHECKVALUE")
+		},
+	}))
+	defer client.Close()
+
+	tests := []struct {
+		code     string
+		bit48    string
+		response string
+	}{
+		{NetworkCodeEcho, "", "00"},
+		{NetworkCodeKeyExchange, "KEY1", "00"},
+		{NetworkCodeKeyExchange, "KEY2", "96"},
+	}
+
+	for _, tt := range tests {
+		request := testRequest(MTINetworkRequest, "000042")
+		request.SetField(70, tt.code)
+		if tt.bit48 != "" {
+			request.SetField(48, tt.bit48)
+		}
+		require.Nil(t, framer.WriteMessage(hostConn, request))
+
+		response := <-responses
+		assert.Equal(t, MTINetworkResponse, response.Mti.Get(), "Expected MTI to be equal")
+		bit39, _ := response.GetField(39)
+		assert.Equal(t, tt.response, bit39, "Expected response code to be equal")
+		bit70, _ := response.GetField(70)
+		assert.Equal(t, tt.code, bit70, "Expected network code to be equal")
+	}
+}
+
+func TestNextSTAN(t *testing.T) {
+	client := &Client{}
+	assert.Equal(t, "000001", client.nextSTAN(), "Expected the first STAN to be 000001")
+	assert.Equal(t, "000002", client.nextSTAN(), "Expected the STAN to be incremented")
+
+	client.stan.Store(999998)
+	assert.Equal(t, "999999", client.nextSTAN(), "Expected the last STAN to be 999999")
+	assert.Equal(t, "000001", client.nextSTAN(), "Expected the STAN to wrap around")
+}
@@ -0,0 +1,31 @@
+package iso8583parser
+
+import "time"
+
+// Option configures the behaviour of an Iso8583Data object
+type Option func(iso *Iso8583Data)
+
+// WithContentTypeWarnings downgrades content type violations to warnings.
+// The field value is accepted and the violation can be retrieved with Warnings.
+func WithContentTypeWarnings() Option {
+	return func(iso *Iso8583Data) {
+		iso.contentTypeWarnings = true
+	}
+}
+
+// WithCollectAllErrors makes Unmarshal continue after a field that violates its length or content type
+// and return every violation, including missing mandatory fields, at once as ValidationErrors.
+// Errors that make the rest of the message unreadable, like a truncated field, still stop parsing.
+func WithCollectAllErrors() Option {
+	return func(iso *Iso8583Data) {
+		iso.collectAllErrors = true
+	}
+}
+
+// WithReferenceTime sets the time UnmarshalStruct takes the parts of a date and time from that are not in the field,
+// like the year of a MMDDhhmmss field. The current time is used by default.
+func WithReferenceTime(ref time.Time) Option {
+	return func(iso *Iso8583Data) {
+		iso.referenceTime = ref
+	}
+}
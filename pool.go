@@ -0,0 +1,310 @@
+package iso8583parser
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Balancing selects the connection of the pool a request is sent on
+type Balancing int
+
+const (
+	BalancingRoundRobin    Balancing = iota // the usable connections take turns
+	BalancingLeastInFlight                  // the usable connection with the fewest requests waiting for a response
+)
+
+// Default reconnect backoff of the pool, the delay doubles after every failed dial up to the maximum
+const (
+	DefaultReconnectMinBackoff = 500 * time.Millisecond
+	DefaultReconnectMaxBackoff = 30 * time.Second
+)
+
+// PoolOption configures the behaviour of a Pool
+type PoolOption func(p *Pool)
+
+// WithBalancing sets how the requests are spread over the connections, BalancingRoundRobin is used by default
+func WithBalancing(balancing Balancing) PoolOption {
+	return func(p *Pool) {
+		p.balancing = balancing
+	}
+}
+
+// WithPoolSize sets the number of connections to every endpoint, 1 is used by default
+func WithPoolSize(size int) PoolOption {
+	return func(p *Pool) {
+		p.size = size
+	}
+}
+
+// WithReconnectBackoff sets the delay before dialing again after a failed dial or a closed connection
+func WithReconnectBackoff(min, max time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.minBackoff = min
+		p.maxBackoff = max
+	}
+}
+
+// WithHealthCheck sends an echo test on every connection on the interval,
+// a connection whose echo test fails is closed and dialed again. The health check is disabled by default
+func WithHealthCheck(interval time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.healthInterval = interval
+	}
+}
+
+// WithDialer sets the function that opens the connections, a TCP dial is used by default
+func WithDialer(dial func(ctx context.Context, addr string) (net.Conn, error)) PoolOption {
+	return func(p *Pool) {
+		p.dial = dial
+	}
+}
+
+// WithClientOptions sets the options of the Client of every connection
+func WithClientOptions(opts ...ClientOption) PoolOption {
+	return func(p *Pool) {
+		p.clientOpts = opts
+	}
+}
+
+// WithPoolErrorHandler sets the function that receives the dial and health check errors, the errors are dropped by default
+func WithPoolErrorHandler(handler func(err error)) PoolOption {
+	return func(p *Pool) {
+		p.onError = handler
+	}
+}
+
+// Pool keeps connections to a list of host endpoints and spreads the requests over them.
+// The endpoints are in order of preference, the requests go to the first endpoint with a usable connection
+// so the next endpoints are only used when the connections to the previous ones are down.
+// Closed connections are dialed again with backoff
+type Pool struct {
+	spec           SpecData
+	endpoints      []string
+	balancing      Balancing
+	size           int
+	minBackoff     time.Duration
+	maxBackoff     time.Duration
+	healthInterval time.Duration
+	dial           func(ctx context.Context, addr string) (net.Conn, error)
+	clientOpts     []ClientOption
+	onError        func(err error)
+
+	conns [][]*poolConn
+	next  atomic.Uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// poolConn is one connection slot of the pool, the client is replaced on every reconnect
+type poolConn struct {
+	mu       sync.Mutex
+	client   *Client
+	inFlight atomic.Int64
+}
+
+// Create a new Pool that connects to the endpoints in order of preference, e.g. the primary and the secondary host.
+// The connections are dialed in the background, Send fails with ErrNoConnection until one of them is usable
+func NewPool(spec SpecData, endpoints []string, opts ...PoolOption) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		spec:       spec,
+		endpoints:  endpoints,
+		size:       1,
+		minBackoff: DefaultReconnectMinBackoff,
+		maxBackoff: DefaultReconnectMaxBackoff,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.dial == nil {
+		dialer := &net.Dialer{}
+		p.dial = func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", addr)
+		}
+	}
+	if p.size < 1 {
+		p.size = 1
+	}
+
+	p.conns = make([][]*poolConn, len(endpoints))
+	for i, addr := range endpoints {
+		p.conns[i] = make([]*poolConn, p.size)
+		for j := range p.conns[i] {
+			pc := &poolConn{}
+			p.conns[i][j] = pc
+
+			p.wg.Add(1)
+			go p.maintain(addr, pc)
+		}
+	}
+
+	return p
+}
+
+// Send the request on a usable connection and wait for the matching response, see Client.Send.
+// A request is not sent again on another connection when its connection fails.
+// An error may occur if no connection is usable (ErrNoConnection) and if the pool is closed (ErrPoolClosed)
+func (p *Pool) Send(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+	if p.ctx.Err() != nil {
+		return nil, ErrPoolClosed
+	}
+
+	pc, client := p.pick()
+	if client == nil {
+		return nil, ErrNoConnection
+	}
+
+	pc.inFlight.Add(1)
+	defer pc.inFlight.Add(-1)
+
+	return client.Send(ctx, request)
+}
+
+// Close the pool and all its connections, waiting requests fail with ErrClientClosed
+func (p *Pool) Close() error {
+	p.cancel()
+	for _, conns := range p.conns {
+		for _, pc := range conns {
+			if client := pc.get(); client != nil {
+				client.Close()
+			}
+		}
+	}
+
+	p.wg.Wait()
+	return nil
+}
+
+// Pick a usable connection of the first endpoint that has one according to the balancing
+func (p *Pool) pick() (*poolConn, *Client) {
+	for _, conns := range p.conns {
+		var usable []*poolConn
+		var clients []*Client
+		for _, pc := range conns {
+			if client := pc.get(); client != nil && client.usable() {
+				usable = append(usable, pc)
+				clients = append(clients, client)
+			}
+		}
+
+		if len(usable) == 0 {
+			continue
+		}
+
+		i := 0
+		switch p.balancing {
+		case BalancingLeastInFlight:
+			for j := range usable {
+				if usable[j].inFlight.Load() < usable[i].inFlight.Load() {
+					i = j
+				}
+			}
+		default:
+			i = int(p.next.Add(1)-1) % len(usable)
+		}
+
+		return usable[i], clients[i]
+	}
+
+	return nil, nil
+}
+
+// Dial the endpoint and keep the connection open until the pool is closed, with backoff between the dials.
+// The backoff is only reset by a connection that passed a health check or stayed up for the maximum backoff,
+// so a host that drops every connection at once is dialed with growing backoff like a host that refuses them
+func (p *Pool) maintain(addr string, pc *poolConn) {
+	defer p.wg.Done()
+
+	backoff := p.minBackoff
+	for {
+		conn, err := p.dial(p.ctx, addr)
+		if err == nil {
+			client := NewClient(conn, p.spec, p.clientOpts...)
+			pc.set(client)
+			if p.ctx.Err() != nil {
+				client.Close()
+				return
+			}
+
+			connected := time.Now()
+			healthy := p.checkHealth(client)
+			pc.set(nil)
+
+			if healthy || time.Since(connected) >= p.maxBackoff {
+				backoff = p.minBackoff
+			}
+		} else {
+			if p.ctx.Err() != nil {
+				return
+			}
+			p.reportError(err)
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, p.maxBackoff)
+	}
+}
+
+// Send the echo tests of the health check until the connection is done, a failed echo test closes the connection.
+// Returns whether at least one echo test passed
+func (p *Pool) checkHealth(client *Client) bool {
+	if p.healthInterval <= 0 {
+		<-client.Done()
+		return false
+	}
+
+	ticker := time.NewTicker(p.healthInterval)
+	defer ticker.Stop()
+
+	healthy := false
+	for {
+		select {
+		case <-client.Done():
+			return healthy
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(p.ctx, p.healthInterval)
+		err := client.Echo(ctx)
+		cancel()
+		if err != nil {
+			p.reportError(err)
+			client.Close()
+			return healthy
+		}
+		healthy = true
+	}
+}
+
+func (p *Pool) reportError(err error) {
+	if p.onError != nil {
+		p.onError(err)
+	}
+}
+
+func (pc *poolConn) get() *Client {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	return pc.client
+}
+
+func (pc *poolConn) set(client *Client) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pc.client = client
+}
@@ -0,0 +1,238 @@
+package iso8583parser
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEndpoint is a host endpoint of the pool tests, every dial starts a new test host on a pipe
+type testEndpoint struct {
+	down     atomic.Bool
+	drop     atomic.Bool
+	noEcho   atomic.Bool
+	dials    atomic.Int32
+	dialed   []time.Time
+	requests atomic.Int32
+	hold     chan struct{}
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func (e *testEndpoint) dial(t *testing.T) (net.Conn, error) {
+	e.dials.Add(1)
+	e.mu.Lock()
+	e.dialed = append(e.dialed, time.Now())
+	e.mu.Unlock()
+	if e.down.Load() {
+		return nil, errors.New("connection refused")
+	}
+
+	clientConn, hostConn := net.Pipe()
+	if e.drop.Load() {
+		hostConn.Close()
+		return clientConn, nil
+	}
+
+	e.mu.Lock()
+	e.conns = append(e.conns, hostConn)
+	e.mu.Unlock()
+
+	framer := NewFramer(FrameHeaderBinary2)
+	runTestHost(t, hostConn, func(request *Iso8583Data) {
+		if request.Mti.Get() == MTINetworkRequest {
+			if !e.noEcho.Load() {
+				go framer.WriteMessage(hostConn, testNetworkResponse(request, "00"))
+			}
+			return
+		}
+
+		e.requests.Add(1)
+		go func() {
+			if e.hold != nil {
+				<-e.hold
+			}
+			framer.WriteMessage(hostConn, testResponse(request, "00"))
+		}()
+	})
+
+	return clientConn, nil
+}
+
+// Close the connections of the endpoint at the host side
+func (e *testEndpoint) closeConns() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, conn := range e.conns {
+		conn.Close()
+	}
+	e.conns = nil
+}
+
+func newTestPool(t *testing.T, endpoints map[string]*testEndpoint, addrs []string, opts ...PoolOption) *Pool {
+	dial := WithDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		return endpoints[addr].dial(t)
+	})
+	backoff := WithReconnectBackoff(5*time.Millisecond, 20*time.Millisecond)
+
+	pool := NewPool(SpecData1987, addrs, append([]PoolOption{dial, backoff}, opts...)...)
+	t.Cleanup(func() { pool.Close() })
+	return pool
+}
+
+// Wait until the pool has the number of usable connections
+func waitPoolReady(t *testing.T, pool *Pool, usable int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		count := 0
+		for _, conns := range pool.conns {
+			for _, pc := range conns {
+				if client := pc.get(); client != nil && client.usable() {
+					count++
+				}
+			}
+		}
+		return count == usable
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestPoolRoundRobin(t *testing.T) {
+	primary := &testEndpoint{}
+	pool := newTestPool(t, map[string]*testEndpoint{"primary": primary}, []string{"primary"}, WithPoolSize(2))
+
+	waitPoolReady(t, pool, 2)
+
+	used := map[*Client]int{}
+	for i := 1; i <= 4; i++ {
+		pc, client := pool.pick()
+		require.NotNil(t, pc)
+		used[client]++
+	}
+	assert.Len(t, used, 2, "Expected both connections to be used")
+	for _, count := range used {
+		assert.Equal(t, 2, count, "Expected the connections to take turns")
+	}
+
+	response, err := pool.Send(context.Background(), testRequest("0200", "000001"))
+	require.Nil(t, err)
+	bit39, _ := response.GetField(39)
+	assert.Equal(t, "00", bit39, "Expected response code to be equal")
+}
+
+func TestPoolLeastInFlight(t *testing.T) {
+	primary := &testEndpoint{hold: make(chan struct{})}
+	pool := newTestPool(t, map[string]*testEndpoint{"primary": primary}, []string{"primary"},
+		WithPoolSize(2), WithBalancing(BalancingLeastInFlight))
+
+	waitPoolReady(t, pool, 2)
+
+	// The first request waits for its response so the second request must go to the other connection
+	first, _ := pool.pick()
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.Send(context.Background(), testRequest("0200", "000001"))
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return first.inFlight.Load() == 1 }, time.Second, time.Millisecond)
+
+	second, _ := pool.pick()
+	assert.NotSame(t, first, second, "Expected the connection without requests in flight")
+
+	close(primary.hold)
+	assert.Nil(t, <-done)
+}
+
+func TestPoolFailover(t *testing.T) {
+	primary := &testEndpoint{}
+	secondary := &testEndpoint{}
+	endpoints := map[string]*testEndpoint{"primary": primary, "secondary": secondary}
+	pool := newTestPool(t, endpoints, []string{"primary", "secondary"})
+
+	waitPoolReady(t, pool, 2)
+	_, err := pool.Send(context.Background(), testRequest("0200", "000001"))
+	require.Nil(t, err)
+	assert.Equal(t, int32(1), primary.requests.Load(), "Expected the primary host to be used")
+
+	// Take the primary host down, the requests fail over to the secondary host
+	primary.down.Store(true)
+	primary.closeConns()
+	require.Eventually(t, func() bool {
+		_, err := pool.Send(context.Background(), testRequest("0200", "000002"))
+		return err == nil && secondary.requests.Load() == 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	// The primary host is used again when it is reconnected
+	dials := primary.dials.Load()
+	primary.down.Store(false)
+	require.Eventually(t, func() bool { return primary.dials.Load() > dials }, 2*time.Second, 5*time.Millisecond)
+	require.Eventually(t, func() bool {
+		_, err := pool.Send(context.Background(), testRequest("0200", "000003"))
+		return err == nil && primary.requests.Load() == 2
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestPoolHealthCheck(t *testing.T) {
+	primary := &testEndpoint{}
+	var errs atomic.Int32
+	pool := newTestPool(t, map[string]*testEndpoint{"primary": primary}, []string{"primary"},
+		WithHealthCheck(20*time.Millisecond), WithPoolErrorHandler(func(err error) { errs.Add(1) }))
+
+	waitPoolReady(t, pool, 1)
+
+	// A connection failing the echo test is closed and dialed again
+	primary.noEcho.Store(true)
+	require.Eventually(t, func() bool { return primary.dials.Load() >= 2 }, 2*time.Second, 5*time.Millisecond)
+	assert.NotZero(t, errs.Load(), "Expected the failed echo test to be reported")
+
+	primary.noEcho.Store(false)
+	waitPoolReady(t, pool, 1)
+	_, err := pool.Send(context.Background(), testRequest("0200", "000001"))
+	assert.Nil(t, err)
+}
+
+func TestPoolNoConnection(t *testing.T) {
+	primary := &testEndpoint{}
+	primary.down.Store(true)
+	pool := newTestPool(t, map[string]*testEndpoint{"primary": primary}, []string{"primary"})
+
+	_, err := pool.Send(context.Background(), testRequest("0200", "000001"))
+	assert.ErrorIs(t, err, ErrNoConnection)
+
+	require.Nil(t, pool.Close())
+	_, err = pool.Send(context.Background(), testRequest("0200", "000001"))
+	assert.ErrorIs(t, err, ErrPoolClosed)
+}
+
+func TestPoolSignOn(t *testing.T) {
+	primary := &testEndpoint{}
+	var pool Sender = newTestPool(t, map[string]*testEndpoint{"primary": primary}, []string{"primary"},
+		WithClientOptions(WithNetworkManagement(NetworkManagement{SignOn: true})))
+
+	// The connection is usable once it is signed on
+	waitPoolReady(t, pool.(*Pool), 1)
+	_, err := pool.Send(context.Background(), testRequest("0200", "000001"))
+	assert.Nil(t, err)
+}
+
+func TestPoolBackoffDroppedConnections(t *testing.T) {
+	primary := &testEndpoint{}
+	primary.drop.Store(true)
+	newTestPool(t, map[string]*testEndpoint{"primary": primary}, []string{"primary"})
+
+	// A host that accepts and drops every connection is dialed with growing backoff
+	require.Eventually(t, func() bool { return primary.dials.Load() >= 4 }, 2*time.Second, time.Millisecond)
+
+	primary.mu.Lock()
+	defer primary.mu.Unlock()
+	assert.GreaterOrEqual(t, primary.dialed[3].Sub(primary.dialed[2]), 20*time.Millisecond, "Expected the backoff to grow")
+}
@@ -0,0 +1,92 @@
+package iso8583parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Prefixer converts the length prefix of a variable length field between its value and the bytes sent over the wire
+type Prefixer interface {
+	// EncodeLength converts the length into a prefix for a field with the given number of length digits (LL = 2, LLL = 3, etc)
+	EncodeLength(length, digits int) ([]byte, error)
+
+	// DecodeLength reads the prefix from the beginning of data and returns the length with the number of bytes read
+	DecodeLength(data []byte, digits int) (length int, read int, err error)
+}
+
+// Get the prefixer for the length encoding name in field spesification
+func getPrefixer(name string) (Prefixer, error) {
+	switch strings.ToLower(name) {
+	case "", EncodingASCII:
+		return textPrefixer{encoder: asciiEncoder{}}, nil
+	case EncodingEBCDIC:
+		return textPrefixer{encoder: ebcdicEncoder{}}, nil
+	case EncodingBCD:
+		return textPrefixer{encoder: bcdEncoder{}}, nil
+	case EncodingBinary:
+		return binaryPrefixer{}, nil
+	}
+
+	return nil, fmt.Errorf("%s is an invalid LenEncoding", name)
+}
+
+// textPrefixer writes the length as zero padded decimal digits using a field encoder
+type textPrefixer struct {
+	encoder Encoder
+}
+
+func (p textPrefixer) EncodeLength(length, digits int) ([]byte, error) {
+	str := strconv.Itoa(length)
+	if len(str) > digits {
+		return nil, fmt.Errorf("length %d does not fit in %d digits prefix", length, digits)
+	}
+
+	return p.encoder.Encode(leftPad(str, digits, "0"))
+}
+
+func (p textPrefixer) DecodeLength(data []byte, digits int) (int, int, error) {
+	str, read, err := p.encoder.Decode(data, digits)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	length, err := strconv.Atoi(str)
+	if err != nil || length < 0 {
+		return 0, 0, fmt.Errorf("prefix %q is not an integer", str)
+	}
+
+	return length, read, nil
+}
+
+// binaryPrefixer writes the length as a big-endian unsigned integer, one byte for every two length digits
+type binaryPrefixer struct{}
+
+func (binaryPrefixer) EncodeLength(length, digits int) ([]byte, error) {
+	size := (digits + 1) / 2
+	if length < 0 || length>>(8*size) != 0 {
+		return nil, fmt.Errorf("length %d does not fit in %d bytes prefix", length, size)
+	}
+
+	prefix := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		prefix[i] = byte(length)
+		length >>= 8
+	}
+
+	return prefix, nil
+}
+
+func (binaryPrefixer) DecodeLength(data []byte, digits int) (int, int, error) {
+	size := (digits + 1) / 2
+	if size > len(data) {
+		return 0, 0, ErrNotEnoughData
+	}
+
+	length := 0
+	for _, b := range data[:size] {
+		length = length<<8 | int(b)
+	}
+
+	return length, size, nil
+}
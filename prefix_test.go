@@ -0,0 +1,87 @@
+package iso8583parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixer(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		length   int
+		digits   int
+		expected []byte
+	}{
+		{name: "ASCII LL", encoding: EncodingASCII, length: 7, digits: 2, expected: []byte("07")},
+		{name: "EBCDIC LLL", encoding: EncodingEBCDIC, length: 15, digits: 3, expected: []byte{0xf0, 0xf1, 0xf5}},
+		{name: "BCD LL", encoding: EncodingBCD, length: 16, digits: 2, expected: []byte{0x16}},
+		{name: "BCD LLL", encoding: EncodingBCD, length: 123, digits: 3, expected: []byte{0x01, 0x23}},
+		{name: "Binary LL", encoding: EncodingBinary, length: 200, digits: 2, expected: []byte{0xc8}},
+		{name: "Binary LLL", encoding: EncodingBinary, length: 300, digits: 3, expected: []byte{0x01, 0x2c}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefixer, err := getPrefixer(tt.encoding)
+			require.Nil(t, err, "Error should be nil")
+
+			prefix, err := prefixer.EncodeLength(tt.length, tt.digits)
+			assert.Nil(t, err, "Error should be nil")
+			assert.Equal(t, tt.expected, prefix, "Expected prefix to be equal")
+
+			length, read, err := prefixer.DecodeLength(append(prefix, 'x'), tt.digits)
+			assert.Nil(t, err, "Error should be nil")
+			assert.Equal(t, len(tt.expected), read, "Expected read bytes to be equal")
+			assert.Equal(t, tt.length, length, "Expected length to be equal")
+		})
+	}
+
+	t.Run("Length overflow", func(t *testing.T) {
+		prefixer, _ := getPrefixer(EncodingASCII)
+		_, err := prefixer.EncodeLength(100, 2)
+		assert.NotNil(t, err, "Expected error prefix overflow")
+	})
+
+	t.Run("Invalid encoding", func(t *testing.T) {
+		_, err := getPrefixer("lbcd")
+		assert.NotNil(t, err, "Expected error length encoding")
+	})
+}
+
+func TestMarshalPrefixEncoding(t *testing.T) {
+	specData := SpecData{
+		Fields: map[int]FieldSpec{
+			2:  {ContentType: "n", LenType: "llvar", MaxLen: 19, Encoding: EncodingBCD, LenEncoding: EncodingBCD},
+			48: {ContentType: "ans", LenType: "lllvar", MaxLen: 999, LenEncoding: EncodingBinary},
+		},
+	}
+
+	isoParser, err := NewFromSpec(specData)
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.AddMTI("0100")
+	isoParser.SetField(2, "4111111111111111")
+	isoParser.SetField(48, "ABC")
+
+	isoMsg, err := isoParser.Marshal()
+	require.Nil(t, err, "Error should be nil")
+
+	expected := []byte("01004000000000010000")
+	expected = append(expected, 0x16, 0x41, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11)
+	expected = append(expected, 0x00, 0x03, 'A', 'B', 'C')
+	require.Equal(t, expected, isoMsg, "Expected iso message to be equal")
+
+	unpacked, err := NewFromSpec(specData)
+	require.Nil(t, err, "Error should be nil")
+
+	err = unpacked.Unmarshal(isoMsg)
+	require.Nil(t, err, "Error should be nil")
+
+	bit2, _ := unpacked.GetField(2)
+	bit48, _ := unpacked.GetField(48)
+	require.Equal(t, "4111111111111111", bit2, "Expected Bit2 to be equal")
+	require.Equal(t, "ABC", bit48, "Expected Bit48 to be equal")
+}
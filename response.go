@@ -0,0 +1,108 @@
+package iso8583parser
+
+import (
+	"fmt"
+)
+
+// DefaultEchoFields are the fields a response echoes when the specification has no echo rule for the request MTI,
+// only fields with the same meaning in ISO 8583:1987, 1993 and 2003 are echoed
+var DefaultEchoFields = []int{2, 3, 4, 7, 11, 32, 37, 41, 42, 49}
+
+// Echo rules of the built-in specifications, the fields a response copies from its request by request MTI
+var (
+	echoFields1987 = map[string][]int{
+		"0100": {2, 3, 4, 7, 11, 12, 13, 32, 37, 41, 42, 49},
+		"0200": {2, 3, 4, 7, 11, 12, 13, 32, 37, 41, 42, 49},
+		"0220": {2, 3, 4, 7, 11, 12, 13, 32, 37, 41, 42, 49},
+		"0400": {2, 3, 4, 7, 11, 12, 13, 32, 37, 41, 42, 49, 90},
+		"0420": {2, 3, 4, 7, 11, 12, 13, 32, 37, 41, 42, 49, 90},
+		"0800": {7, 11, 70},
+	}
+	echoFields1993 = map[string][]int{
+		"1100": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49},
+		"1200": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49},
+		"1220": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49},
+		"1400": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49, 56},
+		"1420": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49, 56},
+		"1804": {7, 11, 24},
+	}
+	echoFields2003 = map[string][]int{
+		"2100": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49},
+		"2200": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49},
+		"2220": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49},
+		"2400": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49, 56},
+		"2420": {2, 3, 4, 7, 11, 12, 24, 32, 37, 41, 42, 49, 56},
+		"2804": {7, 11, 24},
+	}
+)
+
+// NewResponse creates the response of the message with the same specification and options.
+// The response MTI answers the function of the request (e.g. 0200 to 0210, 0220 to 0230 and 0201 to 0210),
+// the header is swapped and the fields of the echo rule of the request MTI are copied, see SpecData.EchoFields.
+// Response fields like 38 and 39 are set on the returned message.
+// An error may occur if the MTI is not set or is already a response (ErrNoResponseMti)
+func (iso *Iso8583Data) NewResponse() (*Iso8583Data, error) {
+	if iso.Mti.Get() == "" || iso.Mti.IsResponse() {
+		return nil, fmt.Errorf("%w: %q", ErrNoResponseMti, iso.Mti.Get())
+	}
+
+	response, err := iso.newSibling()
+	if err != nil {
+		return nil, err
+	}
+
+	// The response of a repeat answers the original request, the origin loses its repeat flag
+	mti := []byte(iso.Mti.Get())
+	mti[2]++
+	if iso.Mti.IsRepeat() {
+		mti[3]--
+	}
+	if err := response.AddMTI(string(mti)); err != nil {
+		return nil, err
+	}
+
+	response.Header = iso.Header.Swap()
+
+	for _, field := range iso.Spec.echoFields(iso.Mti.Get()) {
+		value, exist := iso.Elements.getElement(field)
+		if !exist {
+			continue
+		}
+		if err := response.SetField(field, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// Create an empty message with the specification and options of the message
+func (iso *Iso8583Data) newSibling() (*Iso8583Data, error) {
+	sibling, err := createIsoObject(iso.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	sibling.contentTypeWarnings = iso.contentTypeWarnings
+	sibling.collectAllErrors = iso.collectAllErrors
+	sibling.referenceTime = iso.referenceTime
+	sibling.versionSpecs = iso.versionSpecs
+	return sibling, nil
+}
+
+// Get the fields a response echoes from a request with the MTI,
+// a repeat uses the echo rule of the original request and DefaultEchoFields is used when there is no rule
+func (s SpecData) echoFields(mti string) []int {
+	if fields, ok := s.EchoFields[mti]; ok {
+		return fields
+	}
+
+	original := MtiData{mti: mti}
+	if original.IsRepeat() {
+		if fields, ok := s.EchoFields[mti[:3]+string(mti[3]-1)]; ok {
+			return fields
+		}
+	}
+
+	return DefaultEchoFields
+}
@@ -0,0 +1,158 @@
+package iso8583parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResponse(t *testing.T) {
+	request, _ := NewFromSpec(SpecData1987)
+	request.AddMTI("0200")
+	request.Header = Header{ID: "60", Destination: "0012", Source: "0034"}
+	fields := map[int]string{
+		2:  "4111111111111111",
+		3:  "000000",
+		4:  "000000001500",
+		11: "000123",
+		18: "5999",
+		41: "TERM0001",
+		49: "360",
+	}
+	for field, value := range fields {
+		require.Nil(t, request.SetField(field, value))
+	}
+
+	response, err := request.NewResponse()
+	require.Nil(t, err)
+	require.Nil(t, response.SetField(38, "AB1234"))
+	require.Nil(t, response.SetField(39, "00"))
+
+	assert.Equal(t, "0210", response.Mti.Get(), "Expected response MTI")
+	assert.Equal(t, Header{ID: "60", Destination: "0034", Source: "0012"}, response.Header, "Expected header to be swapped")
+	for field, value := range fields {
+		actual, err := response.GetField(field)
+		if field == 18 {
+			assert.NotNil(t, err, "Expected field 18 not to be echoed")
+			continue
+		}
+		assert.Equal(t, value, actual, "Expected field %d to be echoed", field)
+	}
+
+	_, err = response.Marshal()
+	assert.Nil(t, err)
+}
+
+func TestNewResponseMti(t *testing.T) {
+	tests := []struct {
+		request  string
+		response string
+	}{
+		{"0100", "0110"},
+		{"0201", "0210"},
+		{"0220", "0230"},
+		{"0421", "0430"},
+		{"0800", "0810"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.request, func(t *testing.T) {
+			request, _ := NewFromSpec(SpecData1987)
+			request.AddMTI(tt.request)
+			request.SetField(11, "000001")
+
+			response, err := request.NewResponse()
+			require.Nil(t, err)
+			assert.Equal(t, tt.response, response.Mti.Get(), "Expected response MTI to be equal")
+		})
+	}
+
+	t.Run("Response", func(t *testing.T) {
+		request, _ := NewFromSpec(SpecData1987)
+		request.AddMTI("0210")
+		_, err := request.NewResponse()
+		assert.ErrorIs(t, err, ErrNoResponseMti)
+	})
+
+	t.Run("No MTI", func(t *testing.T) {
+		request, _ := NewFromSpec(SpecData1987)
+		_, err := request.NewResponse()
+		assert.ErrorIs(t, err, ErrNoResponseMti)
+	})
+}
+
+func TestNewResponseEchoFields(t *testing.T) {
+	spec := SpecData1987
+	spec.EchoFields = map[string][]int{"0200": {11, 18}}
+
+	request, _ := NewFromSpec(spec)
+	request.AddMTI("0201")
+	request.SetField(3, "000000")
+	request.SetField(11, "000123")
+	request.SetField(18, "5999")
+
+	response, err := request.NewResponse()
+	require.Nil(t, err)
+	assert.Equal(t, map[int]string{11: "000123", 18: "5999"}, response.Elements.getElements(), "Expected the repeat to use the rule of 0200")
+
+	t.Run("Version specs", func(t *testing.T) {
+		request, _ := NewFromVersionSpecs(VersionSpecs)
+		request.AddMTI("1804")
+		request.SetField(7, "1016120000")
+		request.SetField(11, "000001")
+		request.SetField(24, "831")
+
+		response, err := request.NewResponse()
+		require.Nil(t, err)
+		assert.Equal(t, "1814", response.Mti.Get(), "Expected response MTI to be equal")
+		assert.Equal(t, SpecData1993, response.Spec, "Expected 1993 specification to be selected")
+		bit24, _ := response.GetField(24)
+		assert.Equal(t, "831", bit24, "Expected function code to be echoed")
+	})
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		spec := SpecData1987
+		spec.EchoFields = map[string][]int{"0200": {200}}
+		_, err := NewFromSpec(spec)
+		assert.ErrorIs(t, err, ErrNoFieldSpec)
+	})
+}
+
+func TestNewResponseReversal1993(t *testing.T) {
+	original, _ := NewFromSpec(SpecData1993)
+	original.AddMTI("1200")
+	original.SetField(11, "000123")
+	original.SetField(12, "261016103000")
+	original.SetField(13, "2601")
+	original.SetField(24, "200")
+
+	reversal, err := original.NewReversal()
+	require.Nil(t, err)
+	reversal.SetField(24, "400")
+
+	response, err := reversal.NewResponse()
+	require.Nil(t, err)
+	assert.Equal(t, "1410", response.Mti.Get(), "Expected response MTI to be equal")
+
+	bit24, _ := response.GetField(24)
+	assert.Equal(t, "400", bit24, "Expected function code to be echoed")
+	bit56, _ := response.GetField(56)
+	assert.Equal(t, "1200"+"000123"+"261016103000", bit56[:22], "Expected original data elements to be echoed")
+	_, err = response.GetField(13)
+	assert.NotNil(t, err, "Expected effective date not to be echoed")
+}
+
+func TestNewResponseSpecFile(t *testing.T) {
+	request, err := New("spec1987.yml")
+	require.Nil(t, err)
+	request.AddMTI("0800")
+	request.SetField(2, "4111111111111111")
+	request.SetField(7, "1016103000")
+	request.SetField(11, "000001")
+	request.SetField(70, "301")
+
+	response, err := request.NewResponse()
+	require.Nil(t, err)
+	assert.Equal(t, map[int]string{7: "1016103000", 11: "000001", 70: "301"}, response.Elements.getElements(), "Expected the echo rule of the spec file")
+}
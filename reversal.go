@@ -0,0 +1,131 @@
+package iso8583parser
+
+import (
+	"fmt"
+)
+
+// Original data elements of a reversal per MTI version, the composite field and the source of every subfield.
+// Source field 0 is the MTI of the original message
+var originalDataElements = map[MtiVersion]struct {
+	field   int
+	sources map[int]int
+}{
+	MtiVersion1987: {field: 90, sources: map[int]int{1: 0, 2: 11, 3: 7, 4: 32, 5: 33}},
+	MtiVersion1993: {field: 56, sources: map[int]int{1: 0, 2: 11, 3: 12, 4: 32}},
+	MtiVersion2003: {field: 56, sources: map[int]int{1: 0, 2: 11, 3: 12, 4: 32}},
+}
+
+// NewReversal creates the reversal request of an authorization or financial message (e.g. 0200 to 0400).
+// The reversal copies the fields of the echo rule of the original MTI, see SpecData.EchoFields,
+// and the original data elements are built from the original MTI, STAN, transmission or local date and time
+// and institution identification codes: field 90 in ISO 8583:1987 and field 56 in ISO 8583:1993 and 2003.
+// An error may occur if the message is not an authorization or financial request or advice (ErrNoReversalMti)
+// and if the original data elements field has no subfields in the specification
+func (iso *Iso8583Data) NewReversal() (*Iso8583Data, error) {
+	class := iso.Mti.Class()
+	if (class != MtiClassAuthorization && class != MtiClassFinancial) || iso.Mti.IsResponse() {
+		return nil, fmt.Errorf("%w: %q", ErrNoReversalMti, iso.Mti.Get())
+	}
+
+	reversal, err := iso.newSibling()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reversal.AddMTI(iso.Mti.Get()[:1] + "400"); err != nil {
+		return nil, err
+	}
+	reversal.Header = iso.Header
+
+	for _, field := range iso.Spec.echoFields(iso.Mti.Get()) {
+		value, exist := iso.Elements.getElement(field)
+		if !exist {
+			continue
+		}
+		if err := reversal.SetField(field, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := reversal.setOriginalDataElements(iso); err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// NewAdvice creates the advice of a request with all of its fields, e.g. 0200 to 0220 or 0400 to 0420.
+// An error may occur if the message is not a request (ErrNoAdviceMti)
+func (iso *Iso8583Data) NewAdvice() (*Iso8583Data, error) {
+	if iso.Mti.Get() == "" || !iso.Mti.IsRequest() {
+		return nil, fmt.Errorf("%w: %q", ErrNoAdviceMti, iso.Mti.Get())
+	}
+
+	advice, err := iso.newSibling()
+	if err != nil {
+		return nil, err
+	}
+
+	mti := []byte(iso.Mti.Get())
+	mti[2] = '2'
+	if err := advice.AddMTI(string(mti)); err != nil {
+		return nil, err
+	}
+	advice.Header = iso.Header
+
+	for field, value := range iso.Elements.getElements() {
+		if err := advice.SetField(field, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return advice, nil
+}
+
+// MarkRepeat marks the message as a repeat of a message that was sent before without response,
+// e.g. 0400 becomes 0401 and 0420 becomes 0421. A repeat stays unchanged.
+// An error may occur if the MTI is not set or the repeat is not defined in the version of the MTI
+func (iso *Iso8583Data) MarkRepeat() error {
+	if iso.Mti.Get() == "" {
+		return ErrInvalidMtiLength
+	}
+	if iso.Mti.IsRepeat() {
+		return nil
+	}
+
+	mti := []byte(iso.Mti.Get())
+	mti[3]++
+	return iso.AddMTI(string(mti))
+}
+
+// Set the original data elements of the reversal of the original message
+func (iso *Iso8583Data) setOriginalDataElements(original *Iso8583Data) error {
+	rule, ok := originalDataElements[original.Mti.Version()]
+	if !ok {
+		return nil
+	}
+
+	fieldSpec, ok := iso.Spec.Fields[rule.field]
+	if !ok {
+		return newFieldError(rule.field, PhaseValue, -1, ErrNoFieldSpec)
+	}
+	if len(fieldSpec.Subfields) == 0 {
+		return &FieldError{Field: rule.field, Phase: PhaseValue, Offset: -1, Err: ErrNoSubfieldSpec}
+	}
+
+	for _, sub := range sortedKeys(rule.sources) {
+		value := original.Mti.Get()
+		if source := rule.sources[sub]; source != 0 {
+			var exist bool
+			if value, exist = original.Elements.getElement(source); !exist {
+				continue
+			}
+		}
+
+		if err := iso.SetSubfield(rule.field, sub, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package iso8583parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReversal(t *testing.T) {
+	t.Run("1987", func(t *testing.T) {
+		original, _ := NewFromSpec(SpecData1987)
+		original.AddMTI("0200")
+		original.SetField(3, "000000")
+		original.SetField(4, "000000001500")
+		original.SetField(7, "1016103000")
+		original.SetField(11, "000123")
+		original.SetField(32, "123456")
+		original.SetField(33, "654321")
+		original.SetField(41, "TERM0001")
+
+		reversal, err := original.NewReversal()
+		require.Nil(t, err)
+		assert.Equal(t, "0400", reversal.Mti.Get(), "Expected reversal MTI")
+
+		bit4, _ := reversal.GetField(4)
+		assert.Equal(t, "000000001500", bit4, "Expected amount to be copied")
+		bit90, _ := reversal.GetField(90)
+		assert.Equal(t, "0200"+"000123"+"1016103000"+"00000123456"+"00000654321", bit90, "Expected original data elements to be equal")
+
+		_, err = reversal.Marshal()
+		assert.Nil(t, err)
+	})
+
+	t.Run("2003", func(t *testing.T) {
+		original, _ := NewFromVersionSpecs(VersionSpecs)
+		original.AddMTI("2100")
+		original.SetField(11, "000000000042")
+		original.SetField(12, "20261016103000")
+		original.SetField(32, "123456")
+
+		reversal, err := original.NewReversal()
+		require.Nil(t, err)
+		assert.Equal(t, "2400", reversal.Mti.Get(), "Expected reversal MTI")
+
+		subfields, err := reversal.GetSubfields(56)
+		require.Nil(t, err)
+		assert.Equal(t, map[int]string{1: "2100", 2: "000000000042", 3: "20261016103000", 4: "123456"}, subfields, "Expected original data elements to be equal")
+	})
+
+	t.Run("Not reversible", func(t *testing.T) {
+		for _, mti := range []string{"0210", "0400", "0800"} {
+			original, _ := NewFromSpec(SpecData1987)
+			original.AddMTI(mti)
+			_, err := original.NewReversal()
+			assert.ErrorIs(t, err, ErrNoReversalMti, mti)
+		}
+	})
+
+	t.Run("No subfields", func(t *testing.T) {
+		spec := SpecData1987
+		spec.Fields = make(map[int]FieldSpec, len(SpecData1987.Fields))
+		for field, fieldSpec := range SpecData1987.Fields {
+			spec.Fields[field] = fieldSpec
+		}
+		bit90 := spec.Fields[90]
+		bit90.Subfields = nil
+		spec.Fields[90] = bit90
+
+		original, _ := NewFromSpec(spec)
+		original.AddMTI("0200")
+		_, err := original.NewReversal()
+		assert.ErrorIs(t, err, ErrNoSubfieldSpec)
+	})
+}
+
+func TestNewAdvice(t *testing.T) {
+	original, _ := NewFromSpec(SpecData1987)
+	original.AddMTI("0200")
+	original.SetField(11, "000123")
+	original.SetField(18, "5999")
+
+	reversal, err := original.NewReversal()
+	require.Nil(t, err)
+
+	advice, err := reversal.NewAdvice()
+	require.Nil(t, err)
+	assert.Equal(t, "0420", advice.Mti.Get(), "Expected reversal advice MTI")
+	assert.Equal(t, reversal.Elements.getElements(), advice.Elements.getElements(), "Expected every field to be copied")
+
+	advice, err = original.NewAdvice()
+	require.Nil(t, err)
+	assert.Equal(t, "0220", advice.Mti.Get(), "Expected financial advice MTI")
+
+	_, err = advice.NewAdvice()
+	assert.ErrorIs(t, err, ErrNoAdviceMti)
+}
+
+func TestMarkRepeat(t *testing.T) {
+	tests := []struct {
+		mti    string
+		repeat string
+	}{
+		{"0400", "0401"},
+		{"0420", "0421"},
+		{"0421", "0421"},
+		{"1220", "1221"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mti, func(t *testing.T) {
+			isoParser, _ := NewFromVersionSpecs(VersionSpecs)
+			isoParser.AddMTI(tt.mti)
+			require.Nil(t, isoParser.MarkRepeat())
+			assert.Equal(t, tt.repeat, isoParser.Mti.Get(), "Expected repeat MTI to be equal")
+		})
+	}
+
+	t.Run("No MTI", func(t *testing.T) {
+		isoParser, _ := NewFromSpec(SpecData1987)
+		assert.ErrorIs(t, isoParser.MarkRepeat(), ErrInvalidMtiLength)
+	})
+}
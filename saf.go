@@ -0,0 +1,324 @@
+package iso8583parser
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Default retry backoff of the store-and-forward queue, the delay doubles after every failed send up to the maximum
+const (
+	DefaultSAFMinBackoff = time.Second
+	DefaultSAFMaxBackoff = time.Minute
+)
+
+// SAFOption configures the behaviour of a SAF queue
+type SAFOption func(s *SAF)
+
+// WithSAFBackoff sets the delay before the next attempt after a failed send
+func WithSAFBackoff(min, max time.Duration) SAFOption {
+	return func(s *SAF) {
+		s.minBackoff = min
+		s.maxBackoff = max
+	}
+}
+
+// WithSAFMessageOptions sets the options of the Iso8583Data objects the stored messages are unmarshalled into
+func WithSAFMessageOptions(opts ...Option) SAFOption {
+	return func(s *SAF) {
+		s.messageOpts = opts
+	}
+}
+
+// WithSAFResponseHandler sets the function that receives every delivered message with its response
+func WithSAFResponseHandler(handler func(msg, response *Iso8583Data)) SAFOption {
+	return func(s *SAF) {
+		s.onResponse = handler
+	}
+}
+
+// WithSAFErrorHandler sets the function that receives the errors of failed sends and of stored messages
+// that can not be unmarshalled, the errors are dropped by default. Messages that are removed from the queue
+// without being delivered are reported with ErrSAFMessageDropped
+func WithSAFErrorHandler(handler func(err error)) SAFOption {
+	return func(s *SAF) {
+		s.onError = handler
+	}
+}
+
+// SAF is a store-and-forward queue that delivers advices and reversals when the host is available.
+// The marshalled messages are kept in a file so they survive a restart and are sent one at a time in order of storage.
+// A message is sent again with backoff until a response is received, from the second attempt on it is a repeat
+// (e.g. 0420 becomes 0421). A message is removed as soon as its response is received, whatever the response code,
+// or when sending fails because of the message itself (e.g. it can not be marshalled)
+type SAF struct {
+	path        string
+	spec        SpecData
+	sender      Sender
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	messageOpts []Option
+	onResponse  func(msg, response *Iso8583Data)
+	onError     func(err error)
+
+	mu      sync.Mutex
+	entries [][]byte
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Create a new SAF queue kept in the file at path that sends the messages with sender, e.g. a Client or a Pool.
+// The messages left in the file are sent again, the stored messages are unmarshalled with spec.
+// An error may occur if the file can not be read
+func NewSAF(path string, spec SpecData, sender Sender, opts ...SAFOption) (*SAF, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &SAF{
+		path:       path,
+		spec:       spec,
+		sender:     sender,
+		minBackoff: DefaultSAFMinBackoff,
+		maxBackoff: DefaultSAFMaxBackoff,
+		wake:       make(chan struct{}, 1),
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.load(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go s.forward()
+	return s, nil
+}
+
+// Store the message in the queue, it is written to the file before Store returns.
+// An error may occur if the message can not be marshalled or the file can not be written
+func (s *SAF) Store(msg *Iso8583Data) error {
+	payload, err := msg.Marshal()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.entries = append(s.entries, payload)
+	err = s.persist()
+	if err != nil {
+		s.entries = s.entries[:len(s.entries)-1]
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Len returns the number of messages waiting in the queue
+func (s *SAF) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+// Close stops sending, the messages waiting in the queue stay in the file
+func (s *SAF) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+// Send the messages in order of storage until the queue is closed
+func (s *SAF) forward() {
+	defer close(s.done)
+
+	backoff := s.minBackoff
+	for {
+		s.mu.Lock()
+		var payload []byte
+		if len(s.entries) > 0 {
+			payload = s.entries[0]
+		}
+		s.mu.Unlock()
+
+		if payload == nil {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-s.wake:
+			}
+			continue
+		}
+
+		msg, err := NewFromSpec(s.spec, s.messageOpts...)
+		if err == nil {
+			err = msg.Unmarshal(payload)
+		}
+		if err != nil {
+			// A message that can not be read will never be delivered
+			s.reportError(fmt.Errorf("%w: %w", ErrSAFMessageDropped, err))
+			s.updateHead(payload, nil)
+			continue
+		}
+
+		response, err := s.sender.Send(s.ctx, msg)
+		if err == nil {
+			s.updateHead(payload, nil)
+			if s.onResponse != nil {
+				s.onResponse(msg, response)
+			}
+			backoff = s.minBackoff
+			continue
+		}
+
+		if s.ctx.Err() != nil {
+			return
+		}
+		if isPermanentSendError(err) {
+			// Sending the same message again fails the same way
+			s.reportError(fmt.Errorf("%w: MTI %s: %w", ErrSAFMessageDropped, msg.Mti.Get(), err))
+			s.updateHead(payload, nil)
+			continue
+		}
+		s.reportError(fmt.Errorf("store-and-forward MTI %s: %w", msg.Mti.Get(), err))
+
+		// The next attempts are repeats of the message
+		if !msg.Mti.IsRepeat() {
+			if err := msg.MarkRepeat(); err != nil {
+				s.reportError(err)
+			} else if repeat, err := msg.Marshal(); err != nil {
+				s.reportError(err)
+			} else {
+				s.updateHead(payload, repeat)
+			}
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.maxBackoff)
+	}
+}
+
+// Remove the first entry of the queue when it is still the payload, or replace it when replacement is set
+// The error handler is called after unlocking, so it may use the queue
+func (s *SAF) updateHead(payload, replacement []byte) {
+	s.mu.Lock()
+	if len(s.entries) == 0 || !bytes.Equal(s.entries[0], payload) {
+		s.mu.Unlock()
+		return
+	}
+
+	if replacement != nil {
+		s.entries[0] = replacement
+	} else {
+		s.entries = s.entries[1:]
+	}
+	err := s.persist()
+	s.mu.Unlock()
+
+	if err != nil {
+		s.reportError(err)
+	}
+}
+
+// Check whether a send failed because of the message itself, e.g. it can not be marshalled or matched to its
+// response, instead of the connection. Errors of a connection that is closed while reading are never permanent
+func isPermanentSendError(err error) bool {
+	if errors.Is(err, ErrClientClosed) {
+		return false
+	}
+
+	var fieldErr *FieldError
+	var validationErrs ValidationErrors
+	return errors.Is(err, ErrNoMatchFields) ||
+		errors.Is(err, ErrUndefinedMti) ||
+		errors.Is(err, ErrInvalidHeader) ||
+		errors.Is(err, ErrFrameTooLarge) ||
+		errors.As(err, &fieldErr) ||
+		errors.As(err, &validationErrs)
+}
+
+// Read the entries from the file, a missing file is an empty queue
+func (s *SAF) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		payload, err := hex.DecodeString(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("store-and-forward file %s: %w", s.path, err)
+		}
+		s.entries = append(s.entries, payload)
+	}
+
+	return scanner.Err()
+}
+
+// Write the entries to the file, one hex encoded message per line.
+// The file is replaced at once so a crash while writing keeps the previous entries
+func (s *SAF) persist() error {
+	var buf bytes.Buffer
+	for _, payload := range s.entries {
+		buf.WriteString(hex.EncodeToString(payload))
+		buf.WriteByte('\n')
+	}
+
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
+
+func (s *SAF) reportError(err error) {
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
@@ -0,0 +1,223 @@
+package iso8583parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSender answers the requests with response code 00 after failing the first requests with err,
+// ErrSendTimeout when err is nil
+type testSender struct {
+	mu    sync.Mutex
+	fail  int
+	err   error
+	sent  []string
+	stans []string
+}
+
+func (s *testSender) Send(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sent = append(s.sent, request.Mti.Get())
+	if s.fail != 0 {
+		s.fail--
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, ErrSendTimeout
+	}
+
+	stan, _ := request.GetField(11)
+	s.stans = append(s.stans, stan)
+	response, err := request.NewResponse()
+	if err != nil {
+		return nil, err
+	}
+	return response, response.SetField(39, "00")
+}
+
+func (s *testSender) delivered() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.stans...)
+}
+
+func (s *testSender) attempts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.sent...)
+}
+
+func TestSAFRepeat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saf.queue")
+	sender := &testSender{fail: 2}
+
+	var mu sync.Mutex
+	var responses []string
+	var errs []error
+	saf, err := NewSAF(path, SpecData1987, sender,
+		WithSAFBackoff(time.Millisecond, 5*time.Millisecond),
+		WithSAFResponseHandler(func(msg, response *Iso8583Data) {
+			mu.Lock()
+			defer mu.Unlock()
+			responses = append(responses, response.Mti.Get())
+		}),
+		WithSAFErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+	require.Nil(t, err)
+	defer saf.Close()
+
+	require.Nil(t, saf.Store(testRequest("0420", "000001")))
+	require.Nil(t, saf.Store(testRequest("0220", "000002")))
+
+	require.Eventually(t, func() bool { return saf.Len() == 0 }, 2*time.Second, time.Millisecond)
+	assert.Equal(t, []string{"0420", "0421", "0421", "0220"}, sender.attempts(), "Expected the retries to be repeats")
+	assert.Equal(t, []string{"000001", "000002"}, sender.delivered(), "Expected the messages in order of storage")
+
+	mu.Lock()
+	assert.Equal(t, []string{"0430", "0230"}, responses, "Expected the responses to be handled")
+	assert.Len(t, errs, 2, "Expected the failed sends to be reported")
+	for _, err := range errs {
+		assert.ErrorIs(t, err, ErrSendTimeout)
+	}
+	mu.Unlock()
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.Empty(t, content, "Expected the file to be empty")
+}
+
+func TestSAFRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saf.queue")
+
+	// The host is down until the queue is closed
+	down := &testSender{fail: -1}
+	saf, err := NewSAF(path, SpecData1987, down, WithSAFBackoff(time.Millisecond, time.Millisecond))
+	require.Nil(t, err)
+
+	require.Nil(t, saf.Store(testRequest("0420", "000001")))
+	require.Eventually(t, func() bool { return len(down.attempts()) >= 2 }, 2*time.Second, time.Millisecond)
+	require.Nil(t, saf.Store(testRequest("0420", "000002")))
+	require.Nil(t, saf.Close())
+	assert.Equal(t, 2, saf.Len(), "Expected the messages to stay in the queue")
+
+	up := &testSender{}
+	saf, err = NewSAF(path, SpecData1987, up)
+	require.Nil(t, err)
+	defer saf.Close()
+
+	require.Eventually(t, func() bool { return saf.Len() == 0 }, 2*time.Second, time.Millisecond)
+	assert.Equal(t, []string{"0421", "0420"}, up.attempts(), "Expected the sent message to be a repeat after the restart")
+	assert.Equal(t, []string{"000001", "000002"}, up.delivered(), "Expected the messages in order of storage")
+}
+
+func TestSAFPermanentError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saf.queue")
+	sender := &testSender{fail: 1, err: &FieldError{Field: 4, Phase: PhaseValue, Offset: -1, Err: ErrFieldTooLong}}
+
+	var mu sync.Mutex
+	var errs []error
+	saf, err := NewSAF(path, SpecData1987, sender,
+		WithSAFBackoff(time.Minute, time.Minute),
+		WithSAFErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+	require.Nil(t, err)
+	defer saf.Close()
+
+	require.Nil(t, saf.Store(testRequest("0420", "000001")))
+	require.Nil(t, saf.Store(testRequest("0420", "000002")))
+
+	// The backoff of a minute is not waited for a message that is dropped
+	require.Eventually(t, func() bool { return saf.Len() == 0 }, 2*time.Second, time.Millisecond)
+	assert.Equal(t, []string{"0420", "0420"}, sender.attempts(), "Expected the dropped message not to be retried")
+	assert.Equal(t, []string{"000002"}, sender.delivered(), "Expected the next message to be delivered")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrSAFMessageDropped)
+	assert.ErrorIs(t, errs[0], ErrFieldTooLong)
+}
+
+func TestIsPermanentSendError(t *testing.T) {
+	assert.True(t, isPermanentSendError(ErrNoMatchFields))
+	assert.True(t, isPermanentSendError(ValidationErrors{ErrMissingMandatoryField}))
+	assert.True(t, isPermanentSendError(fmt.Errorf("%w: 9000 bytes exceeds 8192 bytes", ErrFrameTooLarge)))
+	assert.False(t, isPermanentSendError(ErrSendTimeout))
+	assert.False(t, isPermanentSendError(ErrNoConnection))
+	assert.False(t, isPermanentSendError(fmt.Errorf("%w: %w", ErrClientClosed, ErrFrameTooLarge)),
+		"Expected a failed read of the connection not to be permanent")
+}
+
+func TestSAFErrorHandlerUsesQueue(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "queue")
+	require.Nil(t, os.Mkdir(dir, 0o700))
+	sender := &testSender{fail: -1}
+
+	var saf *SAF
+	var mu sync.Mutex
+	var errs []error
+	saf, err := NewSAF(filepath.Join(dir, "saf.queue"), SpecData1987, sender,
+		WithSAFBackoff(time.Millisecond, time.Millisecond),
+		WithSAFErrorHandler(func(err error) {
+			// The directory is removed so writing the repeat to the file fails
+			os.RemoveAll(dir)
+			saf.Len()
+
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+	require.Nil(t, err)
+	defer saf.Close()
+
+	require.Nil(t, saf.Store(testRequest("0420", "000001")))
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, err := range errs {
+			if errors.Is(err, os.ErrNotExist) {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, time.Millisecond, "Expected the write error to be reported without a deadlock")
+}
+
+func TestSAFInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saf.queue")
+	require.Nil(t, os.WriteFile(path, []byte("not hex\n"), 0o600))
+
+	_, err := NewSAF(path, SpecData1987, &testSender{})
+	assert.NotNil(t, err, "Expected error of the invalid file")
+
+	_, err = NewSAF(filepath.Join(t.TempDir(), "missing", "saf.queue"), SpecData1987, &testSender{})
+	assert.Nil(t, err, "Expected a missing file to be an empty queue")
+
+	saf, _ := NewSAF(filepath.Join(t.TempDir(), "missing", "saf.queue"), SpecData1987, &testSender{})
+	defer saf.Close()
+	err = saf.Store(testRequest("0420", "000001"))
+	assert.True(t, errors.Is(err, os.ErrNotExist), "Expected the write error")
+	assert.Equal(t, 0, saf.Len(), "Expected the message not to be queued")
+}
@@ -0,0 +1,339 @@
+package iso8583parser
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// HandlerFunc responds to a request message, a nil response is not written back
+type HandlerFunc func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error)
+
+// Middleware wraps a handler, e.g. to log or validate every request
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// ServerOption configures the behaviour of a Server
+type ServerOption func(s *Server)
+
+// WithServerFramer sets the framing of the messages on the connections, FrameHeaderBinary2 is used by default
+func WithServerFramer(framer Framer) ServerOption {
+	return func(s *Server) {
+		s.framer = framer
+	}
+}
+
+// WithServerMessageOptions sets the options of the Iso8583Data objects the requests are unmarshalled into
+func WithServerMessageOptions(opts ...Option) ServerOption {
+	return func(s *Server) {
+		s.messageOpts = opts
+	}
+}
+
+// WithServerErrorHandler sets the function that receives the errors of requests that can not be unmarshalled,
+// have no handler or fail in the handler. The errors are dropped by default
+func WithServerErrorHandler(handler func(err error)) ServerOption {
+	return func(s *Server) {
+		s.onError = handler
+	}
+}
+
+// Server accepts connections and dispatches the received requests to the handlers registered by MTI,
+// the requests of a connection are handled concurrently and the responses are written back on the same connection
+type Server struct {
+	spec        SpecData
+	framer      Framer
+	messageOpts []Option
+	onError     func(err error)
+
+	mu         sync.Mutex
+	handlers   map[string]HandlerFunc
+	middleware []Middleware
+	listeners  map[net.Listener]struct{}
+	conns      map[*serverConn]struct{}
+	closing    bool
+
+	handling sync.WaitGroup
+}
+
+// serverConn is a connection accepted by the server
+type serverConn struct {
+	conn     net.Conn
+	writeMu  sync.Mutex
+	cancel   context.CancelFunc
+	handling sync.WaitGroup
+}
+
+// Create a new Server that unmarshals the requests with spec
+func NewServer(spec SpecData, opts ...ServerOption) *Server {
+	s := &Server{
+		spec:      spec,
+		framer:    NewFramer(FrameHeaderBinary2),
+		handlers:  make(map[string]HandlerFunc),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[*serverConn]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Handle registers the handler of the requests with the MTI, e.g. "0200"
+func (s *Server) Handle(mti string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[mti] = handler
+}
+
+// Use adds middleware to every handler, the first middleware added is the outermost
+func (s *Server) Use(middleware ...Middleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.middleware = append(s.middleware, middleware...)
+}
+
+// ListenAndServe listens on the TCP address and serves the connections, see Serve
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener and serves each of them in its own goroutine.
+// Serve always returns an error, ErrServerClosed after Shutdown
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		l.Close()
+		return ErrServerClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, l)
+		s.mu.Unlock()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closing := s.closing
+			s.mu.Unlock()
+
+			if closing {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn reads the requests of one connection until it is closed or the server shuts down
+func (s *Server) ServeConn(conn net.Conn) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sc := &serverConn{conn: conn, cancel: cancel}
+
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		cancel()
+		conn.Close()
+		return
+	}
+	s.conns[sc] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		// The responses of the requests being handled are written before the connection is closed
+		sc.handling.Wait()
+		cancel()
+		conn.Close()
+
+		s.mu.Lock()
+		delete(s.conns, sc)
+		s.mu.Unlock()
+	}()
+
+	for {
+		payload, err := s.framer.ReadFrame(conn)
+		if err != nil {
+			return
+		}
+
+		request, err := NewFromSpec(s.spec, s.messageOpts...)
+		if err == nil {
+			err = request.Unmarshal(payload)
+		}
+		if err != nil {
+			s.reportError(err)
+			continue
+		}
+
+		// A request read while shutting down is not handled, the Add must not race with the Wait of Shutdown
+		s.mu.Lock()
+		if s.closing {
+			s.mu.Unlock()
+			return
+		}
+		s.handling.Add(1)
+		sc.handling.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.handling.Done()
+			defer sc.handling.Done()
+			s.handle(ctx, sc, request)
+		}()
+	}
+}
+
+// Dispatch the request to its handler and write back the response
+func (s *Server) handle(ctx context.Context, sc *serverConn, request *Iso8583Data) {
+	s.mu.Lock()
+	handler, ok := s.handlers[request.Mti.Get()]
+	middleware := s.middleware
+	s.mu.Unlock()
+
+	if !ok {
+		s.reportError(fmt.Errorf("%w: MTI %s", ErrNoHandler, request.Mti.Get()))
+		return
+	}
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	response, err := handler(ctx, request)
+	if err != nil {
+		s.reportError(err)
+		return
+	}
+	if response == nil {
+		return
+	}
+
+	payload, err := response.Marshal()
+	if err != nil {
+		s.reportError(err)
+		return
+	}
+
+	sc.writeMu.Lock()
+	defer sc.writeMu.Unlock()
+
+	if err := s.framer.WriteFrame(sc.conn, payload); err != nil {
+		s.reportError(err)
+	}
+}
+
+func (s *Server) reportError(err error) {
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
+
+// Shutdown stops accepting connections and reading requests, waits for the requests being handled
+// to write their responses and closes the connections.
+// When the context is done first the connections are closed at once and the context error is returned
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	for l := range s.listeners {
+		l.Close()
+	}
+	conns := make([]*serverConn, 0, len(s.conns))
+	for sc := range s.conns {
+		conns = append(conns, sc)
+		// Interrupt the read of the next request, the responses can still be written
+		sc.conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	handled := make(chan struct{})
+	go func() {
+		s.handling.Wait()
+		close(handled)
+	}()
+
+	var err error
+	select {
+	case <-handled:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	for _, sc := range conns {
+		sc.cancel()
+		sc.conn.Close()
+	}
+
+	return err
+}
+
+// LoggingMiddleware logs the MTI, STAN, duration and error of every request
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+			start := time.Now()
+			response, err := next(ctx, request)
+
+			stan, _ := request.Elements.getElement(11)
+			attrs := []any{"mti", request.Mti.Get(), "stan", stan, "duration", time.Since(start)}
+			if response != nil {
+				attrs = append(attrs, "response_mti", response.Mti.Get())
+			}
+			if err != nil {
+				logger.ErrorContext(ctx, "iso8583 request failed", append(attrs, "error", err)...)
+			} else {
+				logger.InfoContext(ctx, "iso8583 request", attrs...)
+			}
+
+			return response, err
+		}
+	}
+}
+
+// RecoveryMiddleware turns a panic in the handler into an error wrapping ErrHandlerPanic
+func RecoveryMiddleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request *Iso8583Data) (response *Iso8583Data, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					response = nil
+					err = fmt.Errorf("%w: MTI %s: %v", ErrHandlerPanic, request.Mti.Get(), r)
+				}
+			}()
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// ValidationMiddleware rejects requests that fail Validate, the handler is not called and no response is written
+func ValidationMiddleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+			if err := request.Validate(); err != nil {
+				return nil, fmt.Errorf("MTI %s: %w", request.Mti.Get(), err)
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
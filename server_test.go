@@ -0,0 +1,141 @@
+package iso8583parser
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerHandle(t *testing.T) {
+	errs := make(chan error, 2)
+	server := NewServer(SpecData1987, WithServerErrorHandler(func(err error) {
+		errs <- err
+	}))
+
+	var order []string
+	server.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+			order = append(order, "outer")
+			return next(ctx, request)
+		}
+	}, RecoveryMiddleware(), func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+			order = append(order, "inner")
+			return next(ctx, request)
+		}
+	})
+
+	server.Handle("0200", func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+		return testResponse(request, "00"), nil
+	})
+	server.Handle("0420", func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+		panic("reversal not supported")
+	})
+
+	clientConn, serverConn := net.Pipe()
+	go server.ServeConn(serverConn)
+
+	client := NewClient(clientConn, SpecData1987, WithSendTimeout(200*time.Millisecond))
+	defer client.Close()
+
+	response, err := client.Send(context.Background(), testRequest("0200", "000001"))
+	require.Nil(t, err, "Error should be nil")
+	bit39, _ := response.GetField(39)
+	assert.Equal(t, "00", bit39, "Expected response code to be equal")
+	assert.Equal(t, []string{"outer", "inner"}, order, "Expected middleware order to be equal")
+
+	_, err = client.Send(context.Background(), testRequest("0420", "000002"))
+	assert.ErrorIs(t, err, ErrSendTimeout)
+	assert.ErrorIs(t, <-errs, ErrHandlerPanic)
+
+	_, err = client.Send(context.Background(), testRequest("0100", "000003"))
+	assert.ErrorIs(t, err, ErrSendTimeout)
+	assert.ErrorIs(t, <-errs, ErrNoHandler)
+}
+
+func TestServerMiddleware(t *testing.T) {
+	handled := false
+	handler := ValidationMiddleware()(func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+		handled = true
+		return nil, nil
+	})
+
+	request, _ := NewFromSpec(specWithMandatoryFields())
+	request.AddMTI("0200")
+	request.SetField(3, "000000")
+
+	_, err := handler(context.Background(), request)
+	assert.ErrorIs(t, err, ErrMissingMandatoryField)
+	assert.False(t, handled, "Expected handler not to be called")
+
+	var logs bytes.Buffer
+	logged := LoggingMiddleware(slog.New(slog.NewTextHandler(&logs, nil)))(func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+		return nil, nil
+	})
+
+	request.SetField(11, "000042")
+	_, err = logged(context.Background(), request)
+	require.Nil(t, err, "Error should be nil")
+	assert.Contains(t, logs.String(), "mti=0200 stan=000042", "Expected request to be logged")
+}
+
+func TestServerShutdown(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "Error should be nil")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	server := NewServer(SpecData1987)
+	server.Handle("0200", func(ctx context.Context, request *Iso8583Data) (*Iso8583Data, error) {
+		close(started)
+		<-release
+		return testResponse(request, "00"), nil
+	})
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(l)
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.Nil(t, err, "Error should be nil")
+
+	client := NewClient(conn, SpecData1987)
+	defer client.Close()
+
+	responses := make(chan *Iso8583Data, 1)
+	go func() {
+		response, err := client.Send(context.Background(), testRequest("0200", "000001"))
+		assert.Nil(t, err, "Error should be nil")
+		responses <- response
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- server.Shutdown(context.Background())
+	}()
+
+	select {
+	case <-shutdown:
+		t.Fatal("Expected shutdown to wait for the request being handled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.Nil(t, <-shutdown, "Error should be nil")
+	assert.ErrorIs(t, <-served, ErrServerClosed)
+
+	response := <-responses
+	require.NotNil(t, response, "Expected response to be written before the connection is closed")
+	assert.Equal(t, "0210", response.Mti.Get(), "Expected MTI to be equal")
+
+	<-client.Done()
+}
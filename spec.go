@@ -1,7 +1,9 @@
 package iso8583parser
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
@@ -13,11 +15,32 @@ type FieldSpec struct {
 	MinLen      int    `yaml:"MinLen"`
 	LenType     string `yaml:"LenType"`
 	Label       string `yaml:"Label"`
+	Encoding    string `yaml:"Encoding"`
+	LenEncoding string `yaml:"LenEncoding"`
+	Format      string `yaml:"Format"`
+
+	// Layout is the Go time layout of a date and time field, e.g. "0601" for YYMM.
+	// Without Layout the date and time accessors choose a layout by MaxLen
+	Layout string `yaml:"Layout"`
+
+	// TLV describes the entries of a field with the generic tlv Format
+	TLV *TLVSpec `yaml:"TLV"`
+
+	// Subfields describes the internal structure of a composite field by subfield number,
+	// the subfields are positioned in order of their number
+	Subfields map[int]FieldSpec `yaml:"Subfields"`
 }
 
 // Spec contains the fields that describes an iso8583 specification
+// MandatoryFields lists the fields that must be present for a specific MTI
+// Header describes the TPDU or proprietary header in front of the MTI, nil when the message starts with the MTI
+// EchoFields lists the fields NewResponse copies from a request with a specific MTI
 type SpecData struct {
-	Fields map[int]FieldSpec
+	Fields          map[int]FieldSpec `yaml:"Fields"`
+	BitmapEncoding  string            `yaml:"BitmapEncoding"`
+	MandatoryFields map[string][]int  `yaml:"MandatoryFields"`
+	Header          *HeaderSpec       `yaml:"Header"`
+	EchoFields      map[string][]int  `yaml:"EchoFields"`
 }
 
 // Read specification from the spesific yaml configuration file
@@ -31,7 +54,16 @@ func (s *SpecData) readFromFile(filename string) error {
 		return err
 	}
 
-	return yaml.Unmarshal(content, &s.Fields)
+	if err := yaml.Unmarshal(content, s); err != nil {
+		return err
+	}
+
+	// The file only contains the field list without specification options
+	if len(s.Fields) == 0 {
+		return yaml.Unmarshal(content, &s.Fields)
+	}
+
+	return nil
 }
 
 // Check field excluding Field 0 (MTI) and Field 1 (bitmap auto-generated)
@@ -44,6 +76,115 @@ func (s *SpecData) hasAtLeastOneDataField() bool {
 	return false
 }
 
+// Check that every field can be packed and unpacked,
+// so an invalid LenType or encoding is reported when the specification is loaded instead of in the middle of parsing
+func (s *SpecData) validate() error {
+	if _, err := bitmapBlockLength(s.BitmapEncoding); err != nil {
+		return err
+	}
+
+	if err := s.Header.validate(); err != nil {
+		return err
+	}
+
+	for _, field := range sortedKeys(s.Fields) {
+		if err := s.Fields[field].validate(); err != nil {
+			return fmt.Errorf("field %d: %w", field, err)
+		}
+	}
+
+	for mti, fields := range s.MandatoryFields {
+		for _, field := range fields {
+			if _, ok := s.Fields[field]; !ok {
+				return fmt.Errorf("mandatory field %d for MTI %s: %w", field, mti, ErrNoFieldSpec)
+			}
+		}
+	}
+
+	for mti, fields := range s.EchoFields {
+		for _, field := range fields {
+			if _, ok := s.Fields[field]; !ok {
+				return fmt.Errorf("echo field %d for MTI %s: %w", field, mti, ErrNoFieldSpec)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check the content type, format, LenType, encodings and maximum length of the field spesification
+func (f FieldSpec) validate() error {
+	if !isValidContentType(f.ContentType) {
+		return fmt.Errorf("%s is an %w", f.ContentType, ErrInvalidContentType)
+	}
+
+	if _, err := getEncoder(f.Encoding); err != nil {
+		return err
+	}
+
+	if !isValidFormat(f.Format) {
+		return fmt.Errorf("%s is an invalid Format", f.Format)
+	}
+
+	if strings.ToLower(f.Format) == FormatTLV {
+		if err := f.TLV.validate(); err != nil {
+			return err
+		}
+	}
+
+	if f.MinLen > f.MaxLen {
+		return fmt.Errorf("MinLen %d is greater than MaxLen %d", f.MinLen, f.MaxLen)
+	}
+
+	if strings.ToLower(f.LenType) == "fixed" {
+		return f.validateSubfields()
+	}
+
+	digits, err := getVariableLengthFromString(f.LenType)
+	if err != nil {
+		return err
+	}
+
+	prefixer, err := getPrefixer(f.LenEncoding)
+	if err != nil {
+		return err
+	}
+
+	if _, err := prefixer.EncodeLength(f.MaxLen, digits); err != nil {
+		return fmt.Errorf("MaxLen %d does not fit in %s prefix", f.MaxLen, strings.ToUpper(f.LenType))
+	}
+
+	return f.validateSubfields()
+}
+
+// Check every subfield of a composite field spesification
+func (f FieldSpec) validateSubfields() error {
+	for _, sub := range sortedKeys(f.Subfields) {
+		if sub < 1 {
+			return fmt.Errorf("subfield number must be greater than 0 found %d instead", sub)
+		}
+
+		if err := f.Subfields[sub].validate(); err != nil {
+			return fmt.Errorf("subfield %d: %w", sub, err)
+		}
+	}
+
+	return nil
+}
+
+// Check the data length against MinLen and MaxLen of the field spesification
+func (f FieldSpec) checkLength(field, length int) error {
+	if length > f.MaxLen {
+		return &LengthError{Field: field, MinLen: f.MinLen, MaxLen: f.MaxLen, Length: length, Err: ErrFieldTooLong}
+	}
+
+	if length < f.MinLen {
+		return &LengthError{Field: field, MinLen: f.MinLen, MaxLen: f.MaxLen, Length: length, Err: ErrFieldTooShort}
+	}
+
+	return nil
+}
+
 // Create new SpecData object from the file specification
 // Errors can occur if have an error from file like file not found, failed to read file, etc
 // and when the file does not match the specified specifications
@@ -51,5 +192,9 @@ func SpecFromFile(filename string) (spec SpecData, err error) {
 	if err := spec.readFromFile(filename); err != nil {
 		return spec, err
 	}
+
+	if err := spec.validate(); err != nil {
+		return spec, err
+	}
 	return spec, nil
 }
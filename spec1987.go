@@ -9,17 +9,17 @@ var SpecData1987 = SpecData{
 		4:   {ContentType: "n", Label: "Amount, transaction", LenType: "fixed", MaxLen: 12},
 		5:   {ContentType: "n", Label: "Amount, settlement", LenType: "fixed", MaxLen: 12},
 		6:   {ContentType: "n", Label: "Amount, cardholder billing", LenType: "fixed", MaxLen: 12},
-		7:   {ContentType: "n", Label: "Transmission date & time", LenType: "fixed", MaxLen: 10},
+		7:   {ContentType: "n", Label: "Transmission date & time", LenType: "fixed", MaxLen: 10, Layout: "0102150405"},
 		8:   {ContentType: "n", Label: "Amount, cardholder billing fee", LenType: "fixed", MaxLen: 8},
 		9:   {ContentType: "n", Label: "Conversion rate, settlement", LenType: "fixed", MaxLen: 8},
 		10:  {ContentType: "n", Label: "Conversion rate, cardholder billing", LenType: "fixed", MaxLen: 8},
 		11:  {ContentType: "n", Label: "System trace audit number", LenType: "fixed", MaxLen: 6},
-		12:  {ContentType: "n", Label: "Time, local transaction (hhmmss)", LenType: "fixed", MaxLen: 6},
-		13:  {ContentType: "n", Label: "Date, local transaction (MMDD)", LenType: "fixed", MaxLen: 4},
-		14:  {ContentType: "n", Label: "Date, expiration", LenType: "fixed", MaxLen: 4},
-		15:  {ContentType: "n", Label: "Date, settlement", LenType: "fixed", MaxLen: 4},
-		16:  {ContentType: "n", Label: "Date, conversion", LenType: "fixed", MaxLen: 4},
-		17:  {ContentType: "n", Label: "Date, capture", LenType: "fixed", MaxLen: 4},
+		12:  {ContentType: "n", Label: "Time, local transaction (hhmmss)", LenType: "fixed", MaxLen: 6, Layout: "150405"},
+		13:  {ContentType: "n", Label: "Date, local transaction (MMDD)", LenType: "fixed", MaxLen: 4, Layout: "0102"},
+		14:  {ContentType: "n", Label: "Date, expiration", LenType: "fixed", MaxLen: 4, Layout: "0601"},
+		15:  {ContentType: "n", Label: "Date, settlement", LenType: "fixed", MaxLen: 4, Layout: "0102"},
+		16:  {ContentType: "n", Label: "Date, conversion", LenType: "fixed", MaxLen: 4, Layout: "0102"},
+		17:  {ContentType: "n", Label: "Date, capture", LenType: "fixed", MaxLen: 4, Layout: "0102"},
 		18:  {ContentType: "n", Label: "Merchant type", LenType: "fixed", MaxLen: 4},
 		19:  {ContentType: "n", Label: "Acquiring institution country code", LenType: "fixed", MaxLen: 3},
 		20:  {ContentType: "n", Label: "PAN extended, country code", LenType: "fixed", MaxLen: 3},
@@ -30,10 +30,10 @@ var SpecData1987 = SpecData{
 		25:  {ContentType: "n", Label: "Point of service condition code", LenType: "fixed", MaxLen: 2},
 		26:  {ContentType: "n", Label: "Point of service capture code", LenType: "fixed", MaxLen: 2},
 		27:  {ContentType: "n", Label: "Authorizing identification response length", LenType: "fixed", MaxLen: 1},
-		28:  {ContentType: "an", Label: "Amount, transaction fee", LenType: "fixed", MaxLen: 9},
-		29:  {ContentType: "an", Label: "Amount, settlement fee", LenType: "fixed", MaxLen: 9},
-		30:  {ContentType: "an", Label: "Amount, transaction processing fee", LenType: "fixed", MaxLen: 9},
-		31:  {ContentType: "an", Label: "Amount, settlement processing fee", LenType: "fixed", MaxLen: 9},
+		28:  {ContentType: "x+n", Label: "Amount, transaction fee", LenType: "fixed", MaxLen: 9},
+		29:  {ContentType: "x+n", Label: "Amount, settlement fee", LenType: "fixed", MaxLen: 9},
+		30:  {ContentType: "x+n", Label: "Amount, transaction processing fee", LenType: "fixed", MaxLen: 9},
+		31:  {ContentType: "x+n", Label: "Amount, settlement processing fee", LenType: "fixed", MaxLen: 9},
 		32:  {ContentType: "n", Label: "Acquiring institution identification code", LenType: "llvar", MaxLen: 11},
 		33:  {ContentType: "n", Label: "Forwarding institution identification code", LenType: "llvar", MaxLen: 11},
 		34:  {ContentType: "ns", Label: "Primary account number, extended", LenType: "llvar", MaxLen: 28},
@@ -57,7 +57,7 @@ var SpecData1987 = SpecData{
 		52:  {ContentType: "b", Label: "Personal identification number data", LenType: "fixed", MaxLen: 8},
 		53:  {ContentType: "n", Label: "Security related control information", LenType: "fixed", MaxLen: 16},
 		54:  {ContentType: "an", Label: "Additional amounts", LenType: "lllvar", MaxLen: 120},
-		55:  {ContentType: "ans", Label: "Reserved ISO", LenType: "lllvar", MaxLen: 999},
+		55:  {ContentType: "b", Label: "ICC data - EMV having multiple tags", LenType: "lllvar", MaxLen: 999, Format: FormatBerTLV},
 		56:  {ContentType: "ans", Label: "Reserved ISO", LenType: "lllvar", MaxLen: 999},
 		57:  {ContentType: "ans", Label: "Reserved national", LenType: "lllvar", MaxLen: 999},
 		58:  {ContentType: "ans", Label: "Reserved national", LenType: "lllvar", MaxLen: 999},
@@ -75,7 +75,7 @@ var SpecData1987 = SpecData{
 		70:  {ContentType: "n", Label: "Network management information code", LenType: "fixed", MaxLen: 3},
 		71:  {ContentType: "n", Label: "Message number", LenType: "fixed", MaxLen: 4},
 		72:  {ContentType: "n", Label: "Message number, last", LenType: "fixed", MaxLen: 4},
-		73:  {ContentType: "n", Label: "Date, action (YYMMDD)", LenType: "fixed", MaxLen: 6},
+		73:  {ContentType: "n", Label: "Date, action (YYMMDD)", LenType: "fixed", MaxLen: 6, Layout: "060102"},
 		74:  {ContentType: "n", Label: "Credits, number", LenType: "fixed", MaxLen: 10},
 		75:  {ContentType: "n", Label: "Credits, reversal number", LenType: "fixed", MaxLen: 10},
 		76:  {ContentType: "n", Label: "Debits, number", LenType: "fixed", MaxLen: 10},
@@ -92,14 +92,14 @@ var SpecData1987 = SpecData{
 		87:  {ContentType: "n", Label: "Credits, reversal amount", LenType: "fixed", MaxLen: 16},
 		88:  {ContentType: "n", Label: "Debits, amount", LenType: "fixed", MaxLen: 16},
 		89:  {ContentType: "n", Label: "Debits, reversal amount", LenType: "fixed", MaxLen: 16},
-		90:  {ContentType: "n", Label: "Original data elements", LenType: "fixed", MaxLen: 42},
+		90:  {ContentType: "n", Label: "Original data elements", LenType: "fixed", MaxLen: 42, Subfields: originalDataElements1987},
 		91:  {ContentType: "an", Label: "File update code", LenType: "fixed", MaxLen: 1},
 		92:  {ContentType: "an", Label: "File security code", LenType: "fixed", MaxLen: 2},
 		93:  {ContentType: "an", Label: "Response indicator", LenType: "fixed", MaxLen: 5},
 		94:  {ContentType: "an", Label: "Service indicator", LenType: "fixed", MaxLen: 7},
 		95:  {ContentType: "an", Label: "Replacement amounts", LenType: "fixed", MaxLen: 42},
 		96:  {ContentType: "b", Label: "Message security code", LenType: "fixed", MaxLen: 8},
-		97:  {ContentType: "an", Label: "Amount, net settlement", LenType: "fixed", MaxLen: 17},
+		97:  {ContentType: "x+n", Label: "Amount, net settlement", LenType: "fixed", MaxLen: 17},
 		98:  {ContentType: "ans", Label: "Payee", LenType: "fixed", MaxLen: 25},
 		99:  {ContentType: "n", Label: "Settlement institution identification code", LenType: "llvar", MaxLen: 11},
 		100: {ContentType: "n", Label: "Receiving institution identification code", LenType: "llvar", MaxLen: 11},
@@ -132,4 +132,14 @@ var SpecData1987 = SpecData{
 		127: {ContentType: "ans", Label: "Reserved for private use", LenType: "lllvar", MaxLen: 999},
 		128: {ContentType: "b", Label: "Message authentication code", LenType: "fixed", MaxLen: 8},
 	},
+	EchoFields: echoFields1987,
+}
+
+// Subfields of field 90 (Original data elements) in ISO 8583:1987
+var originalDataElements1987 = map[int]FieldSpec{
+	1: {ContentType: "n", Label: "Original message type indicator", LenType: "fixed", MaxLen: 4},
+	2: {ContentType: "n", Label: "Original system trace audit number", LenType: "fixed", MaxLen: 6},
+	3: {ContentType: "n", Label: "Original transmission date & time", LenType: "fixed", MaxLen: 10, Layout: "0102150405"},
+	4: {ContentType: "n", Label: "Original acquiring institution identification code", LenType: "fixed", MaxLen: 11},
+	5: {ContentType: "n", Label: "Original forwarding institution identification code", LenType: "fixed", MaxLen: 11},
 }
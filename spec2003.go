@@ -0,0 +1,144 @@
+package iso8583parser
+
+var SpecData2003 = SpecData{
+	Fields: map[int]FieldSpec{
+		0:   {ContentType: "n", Label: "Message Type Indicator", LenType: "fixed", MaxLen: 4},
+		1:   {ContentType: "b", Label: "Bitmap", LenType: "fixed", MaxLen: 8},
+		2:   {ContentType: "n", Label: "Primary account number (PAN)", LenType: "llvar", MaxLen: 19, MinLen: 12},
+		3:   {ContentType: "n", Label: "Processing code", LenType: "fixed", MaxLen: 6},
+		4:   {ContentType: "n", Label: "Amount, transaction", LenType: "fixed", MaxLen: 12},
+		5:   {ContentType: "n", Label: "Amount, reconciliation", LenType: "fixed", MaxLen: 12},
+		6:   {ContentType: "n", Label: "Amount, cardholder billing", LenType: "fixed", MaxLen: 12},
+		7:   {ContentType: "n", Label: "Date and time, transmission", LenType: "fixed", MaxLen: 10, Layout: "0102150405"},
+		8:   {ContentType: "n", Label: "Amount, cardholder billing fee", LenType: "fixed", MaxLen: 8},
+		9:   {ContentType: "n", Label: "Conversion rate, reconciliation", LenType: "fixed", MaxLen: 8},
+		10:  {ContentType: "n", Label: "Conversion rate, cardholder billing", LenType: "fixed", MaxLen: 8},
+		11:  {ContentType: "n", Label: "System trace audit number", LenType: "fixed", MaxLen: 12},
+		12:  {ContentType: "n", Label: "Date and time, local transaction (CCYYMMDDhhmmss)", LenType: "fixed", MaxLen: 14, Layout: "20060102150405"},
+		13:  {ContentType: "n", Label: "Date, effective (YYMM)", LenType: "fixed", MaxLen: 4, Layout: "0601"},
+		14:  {ContentType: "n", Label: "Date, expiration", LenType: "fixed", MaxLen: 4, Layout: "0601"},
+		15:  {ContentType: "n", Label: "Date, settlement (CCYYMMDD)", LenType: "fixed", MaxLen: 8, Layout: "20060102"},
+		16:  {ContentType: "n", Label: "Date, conversion", LenType: "fixed", MaxLen: 4, Layout: "0102"},
+		17:  {ContentType: "n", Label: "Date, capture", LenType: "fixed", MaxLen: 4, Layout: "0102"},
+		18:  {ContentType: "n", Label: "Merchant type", LenType: "fixed", MaxLen: 4},
+		19:  {ContentType: "n", Label: "Country code, acquiring institution", LenType: "fixed", MaxLen: 3},
+		20:  {ContentType: "n", Label: "Country code, primary account number", LenType: "fixed", MaxLen: 3},
+		21:  {ContentType: "ans", Label: "Transaction life cycle identification data", LenType: "fixed", MaxLen: 22},
+		22:  {ContentType: "ans", Label: "Point of service data code", LenType: "fixed", MaxLen: 16},
+		23:  {ContentType: "n", Label: "Card sequence number", LenType: "fixed", MaxLen: 3},
+		24:  {ContentType: "n", Label: "Function code", LenType: "fixed", MaxLen: 3},
+		25:  {ContentType: "n", Label: "Message reason code", LenType: "fixed", MaxLen: 4},
+		26:  {ContentType: "n", Label: "Merchant category code", LenType: "fixed", MaxLen: 4},
+		27:  {ContentType: "ans", Label: "Point of service capability", LenType: "llvar", MaxLen: 27},
+		28:  {ContentType: "n", Label: "Date, reconciliation (CCYYMMDD)", LenType: "fixed", MaxLen: 8, Layout: "20060102"},
+		29:  {ContentType: "n", Label: "Reconciliation indicator", LenType: "fixed", MaxLen: 3},
+		30:  {ContentType: "n", Label: "Amounts, original", LenType: "fixed", MaxLen: 24},
+		31:  {ContentType: "ans", Label: "Acquirer reference number", LenType: "llvar", MaxLen: 48},
+		32:  {ContentType: "n", Label: "Acquiring institution identification code", LenType: "llvar", MaxLen: 11},
+		33:  {ContentType: "n", Label: "Forwarding institution identification code", LenType: "llvar", MaxLen: 11},
+		34:  {ContentType: "ans", Label: "Electronic commerce data", LenType: "lllvar", MaxLen: 999},
+		35:  {ContentType: "z", Label: "Track 2 data", LenType: "llvar", MaxLen: 37},
+		36:  {ContentType: "z", Label: "Track 3 data", LenType: "lllvar", MaxLen: 104},
+		37:  {ContentType: "an", Label: "Retrieval reference number", LenType: "fixed", MaxLen: 12},
+		38:  {ContentType: "an", Label: "Approval code", LenType: "fixed", MaxLen: 6},
+		39:  {ContentType: "n", Label: "Action code", LenType: "fixed", MaxLen: 4},
+		40:  {ContentType: "n", Label: "Service code", LenType: "fixed", MaxLen: 3},
+		41:  {ContentType: "ans", Label: "Card acceptor terminal identification", LenType: "fixed", MaxLen: 8},
+		42:  {ContentType: "ans", Label: "Card acceptor identification code", LenType: "llvar", MaxLen: 35},
+		43:  {ContentType: "ans", Label: "Card acceptor name/location", LenType: "lllvar", MaxLen: 999},
+		44:  {ContentType: "ans", Label: "Additional response data", LenType: "llvar", MaxLen: 99},
+		45:  {ContentType: "ans", Label: "Track 1 data", LenType: "llvar", MaxLen: 76},
+		46:  {ContentType: "ans", Label: "Amounts, fees", LenType: "lllvar", MaxLen: 216},
+		47:  {ContentType: "ans", Label: "Additional data - national", LenType: "lllvar", MaxLen: 999},
+		48:  {ContentType: "ans", Label: "Additional data - private", LenType: "llllvar", MaxLen: 9999},
+		49:  {ContentType: "an", Label: "Currency code, transaction", LenType: "fixed", MaxLen: 3},
+		50:  {ContentType: "an", Label: "Currency code, reconciliation", LenType: "fixed", MaxLen: 3},
+		51:  {ContentType: "an", Label: "Currency code, cardholder billing", LenType: "fixed", MaxLen: 3},
+		52:  {ContentType: "b", Label: "Personal identification number (PIN) data", LenType: "fixed", MaxLen: 8},
+		53:  {ContentType: "b", Label: "Security related control information", LenType: "llvar", MaxLen: 48},
+		54:  {ContentType: "ans", Label: "Amounts, additional", LenType: "lllvar", MaxLen: 126},
+		55:  {ContentType: "b", Label: "Integrated circuit card (ICC) system related data", LenType: "lllvar", MaxLen: 999, Format: FormatBerTLV},
+		56:  {ContentType: "n", Label: "Original data elements", LenType: "llvar", MaxLen: 43, Subfields: originalDataElements2003},
+		57:  {ContentType: "n", Label: "Authorization life cycle code", LenType: "fixed", MaxLen: 3},
+		58:  {ContentType: "n", Label: "Authorizing agent institution identification code", LenType: "llvar", MaxLen: 11},
+		59:  {ContentType: "ans", Label: "Transport data", LenType: "lllvar", MaxLen: 999},
+		60:  {ContentType: "ans", Label: "Reserved for national use", LenType: "lllvar", MaxLen: 999},
+		61:  {ContentType: "ans", Label: "Reserved for national use", LenType: "lllvar", MaxLen: 999},
+		62:  {ContentType: "ans", Label: "Reserved for private use", LenType: "lllvar", MaxLen: 999},
+		63:  {ContentType: "ans", Label: "Reserved for private use", LenType: "lllvar", MaxLen: 999},
+		64:  {ContentType: "b", Label: "Message authentication code (MAC)", LenType: "fixed", MaxLen: 8},
+		65:  {ContentType: "b", Label: "Bitmap, extended", LenType: "fixed", MaxLen: 1},
+		66:  {ContentType: "ans", Label: "Amounts, original fees", LenType: "lllvar", MaxLen: 216},
+		67:  {ContentType: "n", Label: "Extended payment data", LenType: "fixed", MaxLen: 2},
+		68:  {ContentType: "n", Label: "Country code, receiving institution", LenType: "fixed", MaxLen: 3},
+		69:  {ContentType: "n", Label: "Country code, settlement institution", LenType: "fixed", MaxLen: 3},
+		70:  {ContentType: "n", Label: "Country code, authorizing agent institution", LenType: "fixed", MaxLen: 3},
+		71:  {ContentType: "n", Label: "Message number", LenType: "fixed", MaxLen: 8},
+		72:  {ContentType: "ans", Label: "Data record", LenType: "llllvar", MaxLen: 9999},
+		73:  {ContentType: "n", Label: "Date, action (CCYYMMDD)", LenType: "fixed", MaxLen: 8, Layout: "20060102"},
+		74:  {ContentType: "n", Label: "Credits, number", LenType: "fixed", MaxLen: 10},
+		75:  {ContentType: "n", Label: "Credits, reversal number", LenType: "fixed", MaxLen: 10},
+		76:  {ContentType: "n", Label: "Debits, number", LenType: "fixed", MaxLen: 10},
+		77:  {ContentType: "n", Label: "Debits, reversal number", LenType: "fixed", MaxLen: 10},
+		78:  {ContentType: "n", Label: "Transfer, number", LenType: "fixed", MaxLen: 10},
+		79:  {ContentType: "n", Label: "Transfer, reversal number", LenType: "fixed", MaxLen: 10},
+		80:  {ContentType: "n", Label: "Inquiries, number", LenType: "fixed", MaxLen: 10},
+		81:  {ContentType: "n", Label: "Authorizations, number", LenType: "fixed", MaxLen: 10},
+		82:  {ContentType: "n", Label: "Inquiries, reversal number", LenType: "fixed", MaxLen: 10},
+		83:  {ContentType: "n", Label: "Payments, number", LenType: "fixed", MaxLen: 10},
+		84:  {ContentType: "n", Label: "Payments, reversal number", LenType: "fixed", MaxLen: 10},
+		85:  {ContentType: "n", Label: "Fee collections, number", LenType: "fixed", MaxLen: 10},
+		86:  {ContentType: "n", Label: "Credits, amount", LenType: "fixed", MaxLen: 16},
+		87:  {ContentType: "n", Label: "Credits, reversal amount", LenType: "fixed", MaxLen: 16},
+		88:  {ContentType: "n", Label: "Debits, amount", LenType: "fixed", MaxLen: 16},
+		89:  {ContentType: "n", Label: "Debits, reversal amount", LenType: "fixed", MaxLen: 16},
+		90:  {ContentType: "n", Label: "Authorizations, reversal number", LenType: "fixed", MaxLen: 10},
+		91:  {ContentType: "n", Label: "Country code, transaction destination institution", LenType: "fixed", MaxLen: 3},
+		92:  {ContentType: "n", Label: "Country code, transaction originator institution", LenType: "fixed", MaxLen: 3},
+		93:  {ContentType: "n", Label: "Transaction destination institution identification code", LenType: "llvar", MaxLen: 11},
+		94:  {ContentType: "n", Label: "Transaction originator institution identification code", LenType: "llvar", MaxLen: 11},
+		95:  {ContentType: "ans", Label: "Card issuer reference data", LenType: "llvar", MaxLen: 99},
+		96:  {ContentType: "b", Label: "Key management data", LenType: "lllvar", MaxLen: 999},
+		97:  {ContentType: "x+n", Label: "Amount, net reconciliation", LenType: "fixed", MaxLen: 17},
+		98:  {ContentType: "ans", Label: "Payee", LenType: "fixed", MaxLen: 25},
+		99:  {ContentType: "an", Label: "Settlement institution identification code", LenType: "llvar", MaxLen: 11},
+		100: {ContentType: "n", Label: "Receiving institution identification code", LenType: "llvar", MaxLen: 11},
+		101: {ContentType: "ans", Label: "File name", LenType: "llvar", MaxLen: 17},
+		102: {ContentType: "ans", Label: "Account identification 1", LenType: "llvar", MaxLen: 28},
+		103: {ContentType: "ans", Label: "Account identification 2", LenType: "llvar", MaxLen: 28},
+		104: {ContentType: "ans", Label: "Transaction description", LenType: "lllvar", MaxLen: 999},
+		105: {ContentType: "n", Label: "Credits, chargeback amount", LenType: "fixed", MaxLen: 16},
+		106: {ContentType: "n", Label: "Debits, chargeback amount", LenType: "fixed", MaxLen: 16},
+		107: {ContentType: "n", Label: "Credits, chargeback number", LenType: "fixed", MaxLen: 10},
+		108: {ContentType: "n", Label: "Debits, chargeback number", LenType: "fixed", MaxLen: 10},
+		109: {ContentType: "ans", Label: "Credits, fee amounts", LenType: "llvar", MaxLen: 84},
+		110: {ContentType: "ans", Label: "Debits, fee amounts", LenType: "llvar", MaxLen: 84},
+		111: {ContentType: "ans", Label: "Reserved for ISO use", LenType: "lllvar", MaxLen: 999},
+		112: {ContentType: "ans", Label: "Reserved for ISO use", LenType: "lllvar", MaxLen: 999},
+		113: {ContentType: "ans", Label: "Reserved for ISO use", LenType: "lllvar", MaxLen: 999},
+		114: {ContentType: "ans", Label: "Reserved for ISO use", LenType: "lllvar", MaxLen: 999},
+		115: {ContentType: "ans", Label: "Reserved for ISO use", LenType: "lllvar", MaxLen: 999},
+		116: {ContentType: "ans", Label: "Reserved for national use", LenType: "lllvar", MaxLen: 999},
+		117: {ContentType: "ans", Label: "Reserved for national use", LenType: "lllvar", MaxLen: 999},
+		118: {ContentType: "ans", Label: "Reserved for national use", LenType: "lllvar", MaxLen: 999},
+		119: {ContentType: "ans", Label: "Reserved for national use", LenType: "lllvar", MaxLen: 999},
+		120: {ContentType: "ans", Label: "Reserved for national use", LenType: "lllvar", MaxLen: 999},
+		121: {ContentType: "ans", Label: "Reserved for national use", LenType: "lllvar", MaxLen: 999},
+		122: {ContentType: "ans", Label: "Reserved for national use", LenType: "lllvar", MaxLen: 999},
+		123: {ContentType: "ans", Label: "Reserved for private use", LenType: "llllvar", MaxLen: 9999},
+		124: {ContentType: "ans", Label: "Reserved for private use", LenType: "llllvar", MaxLen: 9999},
+		125: {ContentType: "ans", Label: "Reserved for private use", LenType: "llllvar", MaxLen: 9999},
+		126: {ContentType: "ans", Label: "Reserved for private use", LenType: "llllvar", MaxLen: 9999},
+		127: {ContentType: "ans", Label: "Reserved for private use", LenType: "llllvar", MaxLen: 9999},
+		128: {ContentType: "b", Label: "Message authentication code (MAC)", LenType: "fixed", MaxLen: 8},
+	},
+	EchoFields: echoFields2003,
+}
+
+// Subfields of field 56 (Original data elements) in ISO 8583:2003
+var originalDataElements2003 = map[int]FieldSpec{
+	1: {ContentType: "n", Label: "Original message type indicator", LenType: "fixed", MaxLen: 4},
+	2: {ContentType: "n", Label: "Original system trace audit number", LenType: "fixed", MaxLen: 12},
+	3: {ContentType: "n", Label: "Original date and time, local transaction", LenType: "fixed", MaxLen: 14, Layout: "20060102150405"},
+	4: {ContentType: "n", Label: "Original acquiring institution identification code", LenType: "llvar", MaxLen: 11},
+}
@@ -0,0 +1,382 @@
+package iso8583parser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default time layouts of date and time fields by the field length, used by GetDateTime and by struct fields without layout tag option
+var defaultTimeLayouts = map[int]string{
+	4:  "0102",
+	6:  "150405",
+	8:  "20060102",
+	10: "0102150405",
+	12: "060102150405",
+	14: "20060102150405",
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// structTag is the parsed iso8583 tag of a struct field, e.g. `iso8583:"7,layout=0102150405,omitempty"`
+type structTag struct {
+	field     int
+	layout    string
+	omitempty bool
+}
+
+// Parse the iso8583 tag of a struct field, ok is false when the struct field has no tag or is unexported
+func parseStructTag(sf reflect.StructField) (tag structTag, ok bool, err error) {
+	value, ok := sf.Tag.Lookup("iso8583")
+	if !ok || value == "-" || !sf.IsExported() {
+		return tag, false, nil
+	}
+
+	parts := strings.Split(value, ",")
+	tag.field, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return tag, false, fmt.Errorf("%w: %s has invalid field number %q", ErrInvalidStruct, sf.Name, parts[0])
+	}
+
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "omitempty":
+			tag.omitempty = true
+		case strings.HasPrefix(opt, "layout="):
+			tag.layout = strings.TrimPrefix(opt, "layout=")
+		default:
+			return tag, false, fmt.Errorf("%w: %s has invalid tag option %q", ErrInvalidStruct, sf.Name, opt)
+		}
+	}
+
+	return tag, true, nil
+}
+
+// Get the struct value behind a non nil pointer to a struct
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%w: expected a non nil pointer to a struct, got %T", ErrInvalidStruct, v)
+	}
+
+	return rv.Elem(), nil
+}
+
+// Define the MTI and the fields of the message from the tagged fields of a struct, v must be a pointer to a struct.
+// Struct fields are tagged with the field number, e.g. `iso8583:"4"`, and field 0 is the MTI. Unexported struct fields are ignored.
+// Supported types are string, integers, time.Time, []byte and structs whose tagged fields are the subfields of a composite field.
+// A nil pointer field is not set, and a zero value is not set when the tag has the omitempty option.
+// A time.Time is formatted with the layout tag option, the Layout of the field or a default layout by the field length
+// An error may occur if a struct field has an unsupported type or its value does not match the field spesification
+func (iso *Iso8583Data) MarshalStruct(v any) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		tag, ok, err := parseStructTag(rt.Field(i))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if tag.omitempty && fv.IsZero() {
+			continue
+		}
+
+		if tag.field == 0 {
+			if fv.Kind() != reflect.String {
+				return fmt.Errorf("%w: MTI must be a string", ErrInvalidStruct)
+			}
+			if err := iso.AddMTI(fv.String()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		spec, ok := iso.Spec.Fields[tag.field]
+		if !ok {
+			return newFieldError(tag.field, PhaseValue, -1, ErrNoFieldSpec)
+		}
+
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			if err := iso.marshalSubfields(tag.field, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := formatStructValue(spec, tag, fv)
+		if err != nil {
+			return newFieldError(tag.field, PhaseValue, -1, err)
+		}
+
+		if err := iso.SetField(tag.field, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Define the subfields of a composite field from the tagged fields of a nested struct
+func (iso *Iso8583Data) marshalSubfields(field int, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		tag, ok, err := parseStructTag(rt.Field(i))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if tag.omitempty && fv.IsZero() {
+			continue
+		}
+
+		subSpec, ok := iso.Spec.Fields[field].Subfields[tag.field]
+		if !ok {
+			return &FieldError{Field: field, Subfield: tag.field, Phase: PhaseValue, Offset: -1, Err: ErrNoSubfieldSpec}
+		}
+
+		value, err := formatStructValue(subSpec, tag, fv)
+		if err != nil {
+			return &FieldError{Field: field, Subfield: tag.field, Phase: PhaseValue, Offset: -1, Err: err}
+		}
+
+		if err := iso.SetSubfield(field, tag.field, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Fill the tagged fields of a struct from the MTI and the fields of the message, v must be a pointer to a struct.
+// The struct fields are tagged and converted like MarshalStruct,
+// a struct field of an absent message field keeps its value and a pointer is allocated only when the message field is present.
+// A time.Time is completed like GetDateTime with the reference time of WithReferenceTime, by default the current time
+// An error may occur if a struct field has an unsupported type or the field value can not be converted
+func (iso *Iso8583Data) UnmarshalStruct(v any) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	ref := iso.now()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		tag, ok, err := parseStructTag(rt.Field(i))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if tag.field == 0 {
+			if iso.Mti.Get() == "" {
+				continue
+			}
+			if err := setStructValue(FieldSpec{}, tag, rv.Field(i), iso.Mti.Get(), ref); err != nil {
+				return newFieldError(0, PhaseMTI, -1, err)
+			}
+			continue
+		}
+
+		value, exist := iso.Elements.getElement(tag.field)
+		if !exist {
+			continue
+		}
+
+		fv := rv.Field(i)
+		ft := fv.Type()
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			if err := iso.unmarshalSubfields(tag.field, fv, ref); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := setStructValue(iso.Spec.Fields[tag.field], tag, fv, value, ref); err != nil {
+			return newFieldError(tag.field, PhaseValue, -1, err)
+		}
+	}
+
+	return nil
+}
+
+// Fill the tagged fields of a nested struct from the subfields of a composite field
+func (iso *Iso8583Data) unmarshalSubfields(field int, fv reflect.Value, ref time.Time) error {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+
+	rt := fv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		tag, ok, err := parseStructTag(rt.Field(i))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		value, exist := iso.Elements.getSubelement(field, tag.field)
+		if !exist {
+			continue
+		}
+
+		subSpec := iso.Spec.Fields[field].Subfields[tag.field]
+		if err := setStructValue(subSpec, tag, fv.Field(i), value, ref); err != nil {
+			return &FieldError{Field: field, Subfield: tag.field, Phase: PhaseValue, Offset: -1, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// Get the reference time of the date and time fields of UnmarshalStruct
+func (iso *Iso8583Data) now() time.Time {
+	if iso.referenceTime.IsZero() {
+		return time.Now()
+	}
+
+	return iso.referenceTime
+}
+
+// Convert a struct field value into the field value
+func formatStructValue(spec FieldSpec, tag structTag, fv reflect.Value) (string, error) {
+	switch {
+	case fv.Type() == timeType:
+		layout, err := timeLayout(spec, tag)
+		if err != nil {
+			return "", err
+		}
+		return fv.Interface().(time.Time).Format(layout), nil
+	case fv.Type() == bytesType:
+		return string(fv.Bytes()), nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return formatFieldInt(spec, fv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if spec.ContentType == ContentTypeSignedAmount {
+			return "C" + strconv.FormatUint(fv.Uint(), 10), nil
+		}
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("%w: unsupported type %s", ErrInvalidStruct, fv.Type())
+}
+
+// Convert the field value into a struct field value, a nil pointer is allocated.
+// The parts of a date and time that are not in the field are taken from ref
+func setStructValue(spec FieldSpec, tag structTag, fv reflect.Value, value string, ref time.Time) error {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+
+	switch {
+	case fv.Type() == timeType:
+		layout, err := timeLayout(spec, tag)
+		if err != nil {
+			return err
+		}
+		t, err := parseDateTime(layout, value, ref)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	case fv.Type() == bytesType:
+		fv.SetBytes([]byte(value))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := parseFieldInt(spec, value)
+		if err != nil {
+			return err
+		}
+		if fv.OverflowInt(n) {
+			return fmt.Errorf("%w: value %d overflows %s", ErrInvalidStruct, n, fv.Type())
+		}
+		fv.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := parseFieldInt(spec, value)
+		if err != nil {
+			return err
+		}
+		if n < 0 || fv.OverflowUint(uint64(n)) {
+			return fmt.Errorf("%w: value %d overflows %s", ErrInvalidStruct, n, fv.Type())
+		}
+		fv.SetUint(uint64(n))
+		return nil
+	}
+
+	return fmt.Errorf("%w: unsupported type %s", ErrInvalidStruct, fv.Type())
+}
+
+// Get the time layout of a date and time field from the tag, from the field spesification
+// or as last resort from the field length
+func timeLayout(spec FieldSpec, tag structTag) (string, error) {
+	if tag.layout != "" {
+		return tag.layout, nil
+	}
+
+	if spec.Layout != "" {
+		return spec.Layout, nil
+	}
+
+	layout, ok := defaultTimeLayouts[spec.MaxLen]
+	if !ok {
+		return "", fmt.Errorf("%w: no default time layout for length %d, use the layout tag option", ErrInvalidStruct, spec.MaxLen)
+	}
+
+	return layout, nil
+}
@@ -0,0 +1,125 @@
+package iso8583parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type originalData struct {
+	MTI  string `iso8583:"1"`
+	STAN int    `iso8583:"2"`
+}
+
+type reversalMessage struct {
+	MTI              string        `iso8583:"0"`
+	PAN              *string       `iso8583:"2"`
+	ProcessingCode   string        `iso8583:"3"`
+	Amount           int64         `iso8583:"4"`
+	TransmissionTime time.Time     `iso8583:"7"`
+	STAN             int           `iso8583:"11"`
+	LocalTime        time.Time     `iso8583:"12,layout=150405"`
+	TransactionFee   int64         `iso8583:"28"`
+	Terminal         string        `iso8583:"41,omitempty"`
+	PINData          []byte        `iso8583:"52"`
+	Original         *originalData `iso8583:"90"`
+	Ignored          string
+}
+
+func TestMarshalStruct(t *testing.T) {
+	msg := reversalMessage{
+		MTI:              "0400",
+		ProcessingCode:   "000000",
+		Amount:           1500,
+		TransmissionTime: time.Date(2026, 7, 11, 17, 2, 15, 0, time.UTC),
+		STAN:             123456,
+		LocalTime:        time.Date(2026, 7, 1, 17, 2, 15, 0, time.UTC),
+		TransactionFee:   -250,
+		PINData:          []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		Original:         &originalData{MTI: "0200", STAN: 123455},
+	}
+
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, isoParser.MarshalStruct(&msg))
+
+	fields, _ := isoParser.GetAllFields()
+	assert.Equal(t, "0400", isoParser.Mti.Get(), "Expected MTI to be equal")
+	assert.NotContains(t, fields, 2, "Expected nil pointer to be skipped")
+	assert.NotContains(t, fields, 41, "Expected omitempty to be skipped")
+	assert.Equal(t, "000000001500", fields[4], "Expected Bit4 to be equal")
+	assert.Equal(t, "0711170215", fields[7], "Expected Bit7 to be equal")
+	assert.Equal(t, "170215", fields[12], "Expected Bit12 to be equal")
+	assert.Equal(t, "D00000250", fields[28], "Expected Bit28 to be equal")
+	assert.Equal(t, "0200"+"123455"+"0000000000"+"00000000000"+"00000000000", fields[90], "Expected Bit90 to be equal")
+
+	isoMsg, err := isoParser.Marshal()
+	require.Nil(t, err, "Error should be nil")
+
+	// The year of field 7 and the date of field 12 are taken from the reference time
+	unpacked, err := NewFromSpec(SpecData1987, WithReferenceTime(time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)))
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, unpacked.Unmarshal(isoMsg))
+
+	var decoded reversalMessage
+	require.Nil(t, unpacked.UnmarshalStruct(&decoded))
+	assert.Equal(t, msg, decoded, "Expected struct to be equal")
+}
+
+func TestUnmarshalStructPointer(t *testing.T) {
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.AddMTI("0200")
+	isoParser.SetField(2, "4111111111111111")
+
+	var msg reversalMessage
+	require.Nil(t, isoParser.UnmarshalStruct(&msg))
+	require.NotNil(t, msg.PAN, "Expected pointer to be allocated")
+	assert.Equal(t, "4111111111111111", *msg.PAN, "Expected PAN to be equal")
+	assert.Nil(t, msg.Original, "Expected absent field to stay nil")
+}
+
+func TestStructUnexportedField(t *testing.T) {
+	type message struct {
+		MTI  string `iso8583:"0"`
+		stan string `iso8583:"11"`
+	}
+
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, isoParser.MarshalStruct(&message{MTI: "0200", stan: "000001"}))
+	_, err = isoParser.GetField(11)
+	assert.NotNil(t, err, "Expected unexported field to be ignored")
+
+	isoParser.SetField(11, "000002")
+	var decoded message
+	require.NotPanics(t, func() { err = isoParser.UnmarshalStruct(&decoded) })
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, message{MTI: "0200"}, decoded, "Expected unexported field to be ignored")
+}
+
+func TestStructErrors(t *testing.T) {
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+
+	err = isoParser.MarshalStruct(reversalMessage{})
+	assert.ErrorIs(t, err, ErrInvalidStruct)
+
+	err = isoParser.MarshalStruct(&struct {
+		Amount float64 `iso8583:"4"`
+	}{Amount: 1.5})
+	assert.ErrorIs(t, err, ErrInvalidStruct)
+
+	err = isoParser.MarshalStruct(&struct {
+		Amount string `iso8583:"four"`
+	}{})
+	assert.ErrorIs(t, err, ErrInvalidStruct)
+
+	err = isoParser.MarshalStruct(&struct {
+		Amount string `iso8583:"4"`
+	}{Amount: "15.00"})
+	assert.ErrorIs(t, err, ErrInvalidContentType)
+}
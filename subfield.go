@@ -0,0 +1,204 @@
+package iso8583parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// setSubelements replace all subelement data of a specific field, nil subelements removes them
+func (e *ElementsData) setSubelements(field int, subelements map[int]string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if subelements == nil {
+		delete(e.subelements, field)
+		return
+	}
+
+	if e.subelements == nil {
+		e.subelements = make(map[int]map[int]string)
+	}
+	e.subelements[field] = subelements
+}
+
+// getSubelement retrieving subelement data based on a specific field and subfield
+func (e *ElementsData) getSubelement(field, sub int) (data string, exist bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	data, exist = e.subelements[field][sub]
+	return
+}
+
+// Define specific subfield data of a composite field by field and subfield number.
+// The field value is composed again from all of its subfields in order of the subfield number,
+// absent fixed subfields are padded and absent variable length subfields are written with zero length.
+// An error may occur if the field or subfield has no spesification,
+// if the data length is outside MinLen and MaxLen of the subfield,
+// and if the data does not match the content type of the subfield
+func (iso *Iso8583Data) SetSubfield(field, sub int, data string) error {
+	if field < 2 || field > bitmapSizeTertiary {
+		return newFieldError(field, PhaseValue, -1, ErrInvalidFieldNumber)
+	}
+
+	fieldSpec, ok := iso.Spec.Fields[field]
+	if !ok {
+		return newFieldError(field, PhaseValue, -1, ErrNoFieldSpec)
+	}
+
+	subSpec, ok := fieldSpec.Subfields[sub]
+	if !ok {
+		return &FieldError{Field: field, Subfield: sub, Phase: PhaseValue, Offset: -1, Err: ErrNoSubfieldSpec}
+	}
+
+	data = iso.Elements.createFieldElement(subSpec, data)
+	if err := subSpec.checkLength(sub, len(data)); err != nil {
+		return asSubfieldError(field, newFieldError(sub, PhaseValue, -1, err))
+	}
+
+	if err := validateContent(sub, subSpec, data); err != nil {
+		if !iso.contentTypeWarnings {
+			return asSubfieldError(field, newFieldError(sub, PhaseValue, -1, err))
+		}
+		iso.addWarning(asSubfieldError(field, newFieldError(sub, PhaseValue, -1, err)))
+	}
+
+	iso.Elements.mu.Lock()
+	defer iso.Elements.mu.Unlock()
+
+	subelements := make(map[int]string, len(iso.Elements.subelements[field])+1)
+	for k, v := range iso.Elements.subelements[field] {
+		subelements[k] = v
+	}
+	subelements[sub] = data
+
+	composed, err := iso.Elements.composeSubfields(field, fieldSpec, subelements)
+	if err != nil {
+		return err
+	}
+
+	if len(composed) > fieldSpec.MaxLen {
+		return newFieldError(field, PhaseValue, -1, fieldSpec.checkLength(field, len(composed)))
+	}
+
+	// Decompose the field value again so the padded subfields are present like after SetField
+	subelements, err = decomposeSubfields(field, fieldSpec, composed)
+	if err != nil {
+		return err
+	}
+
+	if iso.Elements.subelements == nil {
+		iso.Elements.subelements = make(map[int]map[int]string)
+	}
+	iso.Elements.subelements[field] = subelements
+	iso.Elements.elements[field] = composed
+
+	iso.bitmapMu.Lock()
+	iso.Bitmap[field-1] = 1
+	iso.bitmapMu.Unlock()
+
+	return nil
+}
+
+// Retrieves specific subfield data of a composite field by field and subfield number.
+// An error may occur if the field number entered is less than 2 or more than maxField (192)
+// and if the subfield is not exist
+func (iso *Iso8583Data) GetSubfield(field, sub int) (string, error) {
+	if field < 2 || field > bitmapSizeTertiary {
+		return "", fmt.Errorf("expected field to be between %d and %d found %d instead", 2, bitmapSizeTertiary, field)
+	}
+
+	val, eksist := iso.Elements.getSubelement(field, sub)
+	if !eksist {
+		return "", fmt.Errorf("element field %d subfield %d not eksist", field, sub)
+	}
+
+	return val, nil
+}
+
+// Retrieves all subfield data of a composite field by field number.
+// An error may occur if the field has no subfields
+func (iso *Iso8583Data) GetSubfields(field int) (map[int]string, error) {
+	iso.Elements.mu.RLock()
+	defer iso.Elements.mu.RUnlock()
+
+	subelements, eksist := iso.Elements.subelements[field]
+	if !eksist {
+		return nil, fmt.Errorf("element field %d has no subfields", field)
+	}
+
+	allSub := make(map[int]string, len(subelements))
+	for k, v := range subelements {
+		allSub[k] = v
+	}
+
+	return allSub, nil
+}
+
+// Compose the value of a composite field from its subfields.
+// A fixed length field is composed from all subfields, a variable length field up to the last present subfield
+func (e *ElementsData) composeSubfields(field int, spec FieldSpec, subelements map[int]string) (string, error) {
+	keys := sortedKeys(spec.Subfields)
+
+	last := len(keys)
+	if strings.ToLower(spec.LenType) != "fixed" {
+		for last > 0 {
+			if _, ok := subelements[keys[last-1]]; ok {
+				break
+			}
+			last--
+		}
+	}
+
+	var buf []byte
+	for _, sub := range keys[:last] {
+		subSpec := spec.Subfields[sub]
+		packed, err := packField(sub, subSpec, e.createFieldElement(subSpec, subelements[sub]))
+		if err != nil {
+			return "", asSubfieldError(field, err)
+		}
+		buf = append(buf, packed...)
+	}
+
+	return string(buf), nil
+}
+
+// Decompose the value of a composite field into its subfields in order of the subfield number,
+// trailing subfields are absent when the value ends before them
+func decomposeSubfields(field int, spec FieldSpec, data string) (map[int]string, error) {
+	subelements := make(map[int]string)
+	raw := []byte(data)
+
+	pos := 0
+	for _, sub := range sortedKeys(spec.Subfields) {
+		if pos >= len(raw) {
+			break
+		}
+
+		value, _, read, err := unpackField(sub, spec.Subfields[sub], raw[pos:], pos)
+		if err != nil {
+			return nil, asSubfieldError(field, err)
+		}
+
+		subelements[sub] = value
+		pos += read
+	}
+
+	if pos < len(raw) {
+		return nil, &FieldError{Field: field, Phase: PhaseValue, Offset: -1, Expected: pos, Actual: len(raw), Err: ErrSubfieldDataLeft}
+	}
+
+	return subelements, nil
+}
+
+// Turn the FieldError of a subfield into the FieldError of its parent field
+func asSubfieldError(field int, err error) error {
+	fieldErr, ok := err.(*FieldError)
+	if !ok {
+		return err
+	}
+
+	fieldErr.Subfield = fieldErr.Field
+	fieldErr.Field = field
+	return fieldErr
+}
@@ -0,0 +1,126 @@
+package iso8583parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func specWithPrivateSubfields() SpecData {
+	return SpecData{
+		Fields: map[int]FieldSpec{
+			3: {ContentType: "n", LenType: "fixed", MaxLen: 6},
+			48: {ContentType: "ans", LenType: "lllvar", MaxLen: 999, Subfields: map[int]FieldSpec{
+				1: {ContentType: "an", Label: "Terminal type", LenType: "fixed", MaxLen: 2},
+				2: {ContentType: "ans", Label: "Customer reference", LenType: "llvar", MaxLen: 20},
+				3: {ContentType: "n", Label: "Installment count", LenType: "fixed", MaxLen: 2},
+			}},
+		},
+	}
+}
+
+func TestSubfieldFixedPosition(t *testing.T) {
+	isoParser, err := New("spec1987.yml")
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.AddMTI("0400")
+	require.Nil(t, isoParser.SetSubfield(90, 1, "0200"))
+	require.Nil(t, isoParser.SetSubfield(90, 2, "123456"))
+	require.Nil(t, isoParser.SetSubfield(90, 3, "0711170215"))
+	require.Nil(t, isoParser.SetSubfield(90, 4, "12345"))
+
+	bit90, err := isoParser.GetField(90)
+	require.Nil(t, err, "Error should be nil")
+	require.Equal(t, "0200"+"123456"+"0711170215"+"00000012345"+"00000000000", bit90, "Expected Bit90 to be equal")
+
+	// The padded subfields that were not set are present like after SetField
+	sub5, err := isoParser.GetSubfield(90, 5)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, "00000000000", sub5, "Expected padded subfield 5 to be equal")
+
+	isoMsg, err := isoParser.MarshalString()
+	require.Nil(t, err, "Error should be nil")
+
+	unpacked, err := New("spec1987.yml")
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, unpacked.UnmarshalString(isoMsg))
+
+	sub2, err := unpacked.GetSubfield(90, 2)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, "123456", sub2, "Expected subfield 2 to be equal")
+
+	sub5, err = unpacked.GetSubfield(90, 5)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, "00000000000", sub5, "Expected subfield 5 to be equal")
+}
+
+func TestSubfieldVariableLength(t *testing.T) {
+	isoParser, err := NewFromSpec(specWithPrivateSubfields())
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.AddMTI("0200")
+	isoParser.SetField(3, "000000")
+	require.Nil(t, isoParser.SetSubfield(48, 2, "INV-001"))
+	require.Nil(t, isoParser.SetSubfield(48, 1, "T1"))
+
+	bit48, _ := isoParser.GetField(48)
+	require.Equal(t, "T1"+"07INV-001", bit48, "Expected trailing subfield to be omitted")
+
+	isoMsg, err := isoParser.MarshalString()
+	require.Nil(t, err, "Error should be nil")
+	require.Equal(t, "0200"+"2000000000010000"+"000000"+"011T107INV-001", isoMsg, "Expected iso message to be equal")
+
+	unpacked, err := NewFromSpec(specWithPrivateSubfields())
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, unpacked.UnmarshalString(isoMsg))
+
+	subs, err := unpacked.GetSubfields(48)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, map[int]string{1: "T1", 2: "INV-001"}, subs, "Expected subfields to be equal")
+
+	_, err = unpacked.GetSubfield(48, 3)
+	assert.NotNil(t, err, "Expected error subfield not exist")
+}
+
+func TestSubfieldErrors(t *testing.T) {
+	t.Run("Unknown subfield", func(t *testing.T) {
+		isoParser, err := NewFromSpec(specWithPrivateSubfields())
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.SetSubfield(48, 9, "X")
+		assert.ErrorIs(t, err, ErrNoSubfieldSpec)
+	})
+
+	t.Run("Subfield content type", func(t *testing.T) {
+		isoParser, err := NewFromSpec(specWithPrivateSubfields())
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.SetSubfield(48, 3, "1X")
+		var fieldErr *FieldError
+		require.True(t, errors.As(err, &fieldErr), "Expected field error")
+		assert.Equal(t, 48, fieldErr.Field, "Expected field to be equal")
+		assert.Equal(t, 3, fieldErr.Subfield, "Expected subfield to be equal")
+		assert.ErrorIs(t, err, ErrInvalidContentType)
+	})
+
+	t.Run("Data left after last subfield", func(t *testing.T) {
+		isoParser, err := NewFromSpec(specWithPrivateSubfields())
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.SetField(48, "T107INV-00112EXTRA")
+		assert.ErrorIs(t, err, ErrSubfieldDataLeft)
+	})
+
+	t.Run("Truncated subfield", func(t *testing.T) {
+		isoParser, err := NewFromSpec(specWithPrivateSubfields())
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.SetField(48, "T109INV")
+		var fieldErr *FieldError
+		require.True(t, errors.As(err, &fieldErr), "Expected field error")
+		assert.Equal(t, 2, fieldErr.Subfield, "Expected subfield to be equal")
+		assert.ErrorIs(t, err, ErrValueTooShort)
+	})
+}
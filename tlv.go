@@ -0,0 +1,468 @@
+package iso8583parser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Supported field formats. An empty format means the field value is a plain string.
+const (
+	FormatBerTLV = "ber-tlv" // BER-TLV as used by ICC (EMV) data in field 55
+	FormatTLV    = "tlv"     // Tag-length-value with a fixed tag width and length width, configured with TLVSpec
+)
+
+// Order of the tag and the length in the entries of a generic TLV formatted field
+const (
+	TLVOrderTagFirst    = "tlv"
+	TLVOrderLengthFirst = "ltv"
+)
+
+// TLVSpec describes the entries of a generic TLV formatted field
+type TLVSpec struct {
+	TagLen         int    `yaml:"TagLen"`         // tag width in characters
+	LenDigits      int    `yaml:"LenDigits"`      // length width in digits, encoded like a LenType prefix
+	LenEncoding    string `yaml:"LenEncoding"`    // ascii (default), ebcdic, bcd or binary
+	Order          string `yaml:"Order"`          // tlv (default) or ltv
+	LenIncludesTag bool   `yaml:"LenIncludesTag"` // the length counts the tag and the value instead of the value only
+}
+
+// TLV is a tag-length-value entry of a TLV formatted field.
+// A constructed BER-TLV tag has its entries in Children instead of Value.
+type TLV struct {
+	Tag      string
+	Value    []byte
+	Children []TLV
+}
+
+// Check whether the format in field spesification is supported
+func isValidFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case "", FormatBerTLV, FormatTLV:
+		return true
+	}
+
+	return false
+}
+
+// Check the entry layout of a generic TLV formatted field
+func (t *TLVSpec) validate() error {
+	if t == nil {
+		return fmt.Errorf("%s format requires TLV spesification", FormatTLV)
+	}
+
+	if t.TagLen < 1 || t.LenDigits < 1 {
+		return fmt.Errorf("TLV TagLen and LenDigits must be greater than 0")
+	}
+
+	if _, err := getPrefixer(t.LenEncoding); err != nil {
+		return err
+	}
+
+	switch strings.ToLower(t.Order) {
+	case "", TLVOrderTagFirst, TLVOrderLengthFirst:
+		return nil
+	}
+
+	return fmt.Errorf("%s is an invalid TLV Order", t.Order)
+}
+
+// Decode the entries of a generic TLV formatted field in the order they appear
+func decodeTLV(t *TLVSpec, data []byte) ([]TLV, error) {
+	prefixer, err := getPrefixer(t.LenEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	lengthFirst := strings.ToLower(t.Order) == TLVOrderLengthFirst
+	tlvs := make([]TLV, 0)
+
+	pos := 0
+	for pos < len(data) {
+		var (
+			tag    string
+			length int
+			read   int
+		)
+
+		if lengthFirst {
+			length, read, err = prefixer.DecodeLength(data[pos:], t.LenDigits)
+			if err != nil {
+				return nil, fmt.Errorf("TLV length at index %d: %w", pos, err)
+			}
+			pos += read
+		}
+
+		if pos+t.TagLen > len(data) {
+			return nil, fmt.Errorf("TLV tag at index %d: %w", pos, ErrNotEnoughData)
+		}
+		tag = string(data[pos : pos+t.TagLen])
+		pos += t.TagLen
+
+		if !lengthFirst {
+			length, read, err = prefixer.DecodeLength(data[pos:], t.LenDigits)
+			if err != nil {
+				return nil, fmt.Errorf("TLV tag %s length: %w", tag, err)
+			}
+			pos += read
+		}
+
+		if t.LenIncludesTag {
+			length -= t.TagLen
+		}
+
+		if length < 0 || pos+length > len(data) {
+			return nil, fmt.Errorf("TLV tag %s value: %w", tag, ErrNotEnoughData)
+		}
+
+		tlvs = append(tlvs, TLV{Tag: tag, Value: append([]byte{}, data[pos:pos+length]...)})
+		pos += length
+	}
+
+	return tlvs, nil
+}
+
+// Encode the entries of a generic TLV formatted field in the given order
+func encodeTLV(t *TLVSpec, tlvs []TLV) ([]byte, error) {
+	prefixer, err := getPrefixer(t.LenEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	for _, tlv := range tlvs {
+		if len(tlv.Tag) != t.TagLen {
+			return nil, fmt.Errorf("TLV tag %q must be length (%d)", tlv.Tag, t.TagLen)
+		}
+
+		length := len(tlv.Value)
+		if t.LenIncludesTag {
+			length += t.TagLen
+		}
+
+		prefix, err := prefixer.EncodeLength(length, t.LenDigits)
+		if err != nil {
+			return nil, fmt.Errorf("TLV tag %s: %w", tlv.Tag, err)
+		}
+
+		if strings.ToLower(t.Order) == TLVOrderLengthFirst {
+			buf = append(buf, prefix...)
+			buf = append(buf, tlv.Tag...)
+		} else {
+			buf = append(buf, tlv.Tag...)
+			buf = append(buf, prefix...)
+		}
+		buf = append(buf, tlv.Value...)
+	}
+
+	return buf, nil
+}
+
+// Decode the entries of a TLV formatted field according to its format
+func decodeFieldTLV(spec FieldSpec, data []byte) ([]TLV, error) {
+	if strings.ToLower(spec.Format) == FormatTLV {
+		return decodeTLV(spec.TLV, data)
+	}
+
+	return DecodeBerTLV(data)
+}
+
+// Encode the entries of a TLV formatted field according to its format
+func encodeFieldTLV(spec FieldSpec, tlvs []TLV) ([]byte, error) {
+	if strings.ToLower(spec.Format) == FormatTLV {
+		return encodeTLV(spec.TLV, tlvs)
+	}
+
+	return EncodeBerTLV(tlvs)
+}
+
+// DecodeBerTLV decodes BER-TLV data into an ordered list of entries.
+// Multi-byte tags, constructed tags and long form lengths are supported,
+// 0x00 and 0xFF padding bytes between entries are skipped.
+func DecodeBerTLV(data []byte) ([]TLV, error) {
+	tlvs := make([]TLV, 0)
+
+	pos := 0
+	for pos < len(data) {
+		if data[pos] == 0x00 || data[pos] == 0xFF {
+			pos++
+			continue
+		}
+
+		tagStart := pos
+		constructed := data[pos]&0x20 != 0
+		if data[pos]&0x1F == 0x1F {
+			pos++
+			for pos < len(data) && data[pos]&0x80 != 0 {
+				pos++
+			}
+		}
+		pos++
+		if pos > len(data) {
+			return nil, fmt.Errorf("BER-TLV tag at index %d: %w", tagStart, ErrNotEnoughData)
+		}
+		tag := strings.ToUpper(hex.EncodeToString(data[tagStart:pos]))
+
+		if pos >= len(data) {
+			return nil, fmt.Errorf("BER-TLV tag %s length: %w", tag, ErrNotEnoughData)
+		}
+
+		length := int(data[pos])
+		pos++
+		if length&0x80 != 0 {
+			size := length & 0x7F
+			if size == 0 || size > 4 {
+				return nil, fmt.Errorf("BER-TLV tag %s has invalid length form 0x%02x", tag, length)
+			}
+			if pos+size > len(data) {
+				return nil, fmt.Errorf("BER-TLV tag %s length: %w", tag, ErrNotEnoughData)
+			}
+
+			length = 0
+			for _, b := range data[pos : pos+size] {
+				length = length<<8 | int(b)
+			}
+			pos += size
+		}
+
+		if pos+length > len(data) {
+			return nil, fmt.Errorf("BER-TLV tag %s value: %w", tag, ErrNotEnoughData)
+		}
+
+		value := data[pos : pos+length]
+		pos += length
+
+		tlv := TLV{Tag: tag}
+		if constructed {
+			children, err := DecodeBerTLV(value)
+			if err != nil {
+				return nil, fmt.Errorf("BER-TLV tag %s: %w", tag, err)
+			}
+			tlv.Children = children
+		} else {
+			tlv.Value = append([]byte{}, value...)
+		}
+
+		tlvs = append(tlvs, tlv)
+	}
+
+	return tlvs, nil
+}
+
+// EncodeBerTLV encodes the entries as BER-TLV in the given order using the shortest length form
+func EncodeBerTLV(tlvs []TLV) ([]byte, error) {
+	var buf []byte
+	for _, tlv := range tlvs {
+		tag, err := hex.DecodeString(tlv.Tag)
+		if err != nil || !isValidBerTag(tag) {
+			return nil, fmt.Errorf("invalid BER-TLV tag %q", tlv.Tag)
+		}
+
+		value := tlv.Value
+		if len(tlv.Children) > 0 {
+			value, err = EncodeBerTLV(tlv.Children)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		buf = append(buf, tag...)
+		buf = append(buf, encodeBerLength(len(value))...)
+		buf = append(buf, value...)
+	}
+
+	return buf, nil
+}
+
+// Check whether the bytes are one complete BER-TLV tag. The subsequent bytes of a multi-byte tag
+// have the continuation bit set except for the last one
+func isValidBerTag(tag []byte) bool {
+	if len(tag) == 0 {
+		return false
+	}
+
+	if tag[0]&0x1F != 0x1F {
+		return len(tag) == 1
+	}
+
+	if len(tag) < 2 {
+		return false
+	}
+	for i, b := range tag[1:] {
+		last := i == len(tag)-2
+		if (b&0x80 != 0) == last {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Encode the BER-TLV length, short form below 128 and long form otherwise
+func encodeBerLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+
+	var size []byte
+	for l := length; l > 0; l >>= 8 {
+		size = append([]byte{byte(l)}, size...)
+	}
+
+	return append([]byte{0x80 | byte(len(size))}, size...)
+}
+
+// Find the entry of a tag in the entries, including the children of constructed tags
+func findTLV(tlvs []TLV, tag string) (TLV, bool) {
+	for _, tlv := range tlvs {
+		if tlv.Tag == tag {
+			return tlv, true
+		}
+
+		if found, ok := findTLV(tlv.Children, tag); ok {
+			return found, true
+		}
+	}
+
+	return TLV{}, false
+}
+
+// Replace the value of a tag in the entries, including the children of constructed tags
+func replaceTLV(tlvs []TLV, tag string, value []byte) bool {
+	for i := range tlvs {
+		if tlvs[i].Tag == tag {
+			tlvs[i] = TLV{Tag: tag, Value: value}
+			return true
+		}
+
+		if replaceTLV(tlvs[i].Children, tag, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get the TLV field spesification
+// Errors can occur if the field has no spesification or is not TLV formatted
+func (iso *Iso8583Data) tlvFieldSpec(field int) (FieldSpec, error) {
+	spec, ok := iso.Spec.Fields[field]
+	if !ok {
+		return spec, newFieldError(field, PhaseValue, -1, ErrNoFieldSpec)
+	}
+
+	if spec.Format == "" {
+		return spec, newFieldError(field, PhaseValue, -1, ErrNotTLVField)
+	}
+
+	return spec, nil
+}
+
+// Retrieves all entries of a TLV formatted field in the order they appear in the field.
+// An error may occur if the field is not TLV formatted or its value can not be decoded
+func (iso *Iso8583Data) GetTags(field int) ([]TLV, error) {
+	spec, err := iso.tlvFieldSpec(field)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := iso.GetField(field)
+	if err != nil {
+		return nil, err
+	}
+
+	tlvs, err := decodeFieldTLV(spec, []byte(data))
+	if err != nil {
+		return nil, newFieldError(field, PhaseValue, -1, err)
+	}
+
+	return tlvs, nil
+}
+
+// Retrieves all entries of a TLV formatted field as a map of tag and value,
+// for BER-TLV the entries inside constructed tags are included.
+// An error may occur if the field is not TLV formatted or its value can not be decoded
+func (iso *Iso8583Data) GetTagMap(field int) (map[string]string, error) {
+	tlvs, err := iso.GetTags(field)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string)
+	var collect func(tlvs []TLV)
+	collect = func(tlvs []TLV) {
+		for _, tlv := range tlvs {
+			if len(tlv.Children) > 0 {
+				collect(tlv.Children)
+				continue
+			}
+			tags[tlv.Tag] = string(tlv.Value)
+		}
+	}
+	collect(tlvs)
+
+	return tags, nil
+}
+
+// Retrieves the value of a tag (e.g. "9F26") of a TLV formatted field.
+// An error may occur if the field is not TLV formatted or the tag is not exist
+func (iso *Iso8583Data) GetTag(field int, tag string) ([]byte, error) {
+	tlvs, err := iso.GetTags(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(iso.Spec.Fields[field].Format) == FormatBerTLV {
+		tag = strings.ToUpper(tag)
+	}
+
+	tlv, ok := findTLV(tlvs, tag)
+	if !ok {
+		return nil, fmt.Errorf("element field %d tag %s not eksist", field, tag)
+	}
+
+	return tlv.Value, nil
+}
+
+// Define the value of a tag (e.g. "9F26") of a TLV formatted field.
+// An existing tag keeps its position, also inside a constructed tag, a new tag is appended after the existing tags
+func (iso *Iso8583Data) SetTag(field int, tag string, value []byte) error {
+	spec, err := iso.tlvFieldSpec(field)
+	if err != nil {
+		return err
+	}
+
+	tlvs := make([]TLV, 0)
+	if _, exist := iso.Elements.getElement(field); exist {
+		current, err := iso.GetTags(field)
+		if err != nil {
+			return err
+		}
+		tlvs = current
+	}
+
+	if strings.ToLower(spec.Format) == FormatBerTLV {
+		tag = strings.ToUpper(tag)
+	}
+
+	if !replaceTLV(tlvs, tag, value) {
+		tlvs = append(tlvs, TLV{Tag: tag, Value: value})
+	}
+
+	return iso.SetTags(field, tlvs)
+}
+
+// Define all entries of a TLV formatted field, the entries are encoded in the given order
+func (iso *Iso8583Data) SetTags(field int, tlvs []TLV) error {
+	spec, err := iso.tlvFieldSpec(field)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := encodeFieldTLV(spec, tlvs)
+	if err != nil {
+		return newFieldError(field, PhaseValue, -1, err)
+	}
+
+	return iso.SetField(field, string(encoded))
+}
@@ -0,0 +1,215 @@
+package iso8583parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var iccData = []byte{
+	0x9F, 0x26, 0x08, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88,
+	0x5F, 0x2A, 0x02, 0x03, 0x60,
+	0x70, 0x07, 0x9F, 0x36, 0x02, 0x00, 0x01, 0x95, 0x00,
+}
+
+func TestDecodeBerTLV(t *testing.T) {
+	t.Run("Positive", func(t *testing.T) {
+		tlvs, err := DecodeBerTLV(iccData)
+		require.Nil(t, err, "Error should be nil")
+
+		expected := []TLV{
+			{Tag: "9F26", Value: []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88}},
+			{Tag: "5F2A", Value: []byte{0x03, 0x60}},
+			{Tag: "70", Children: []TLV{
+				{Tag: "9F36", Value: []byte{0x00, 0x01}},
+				{Tag: "95", Value: []byte{}},
+			}},
+		}
+		assert.Equal(t, expected, tlvs, "Expected entries to be equal")
+	})
+
+	t.Run("Long form length", func(t *testing.T) {
+		value := make([]byte, 200)
+		data := append([]byte{0x9F, 0x10, 0x81, 0xC8}, value...)
+
+		tlvs, err := DecodeBerTLV(data)
+		require.Nil(t, err, "Error should be nil")
+		require.Len(t, tlvs, 1, "Expected one entry")
+		assert.Equal(t, "9F10", tlvs[0].Tag, "Expected tag to be equal")
+		assert.Len(t, tlvs[0].Value, 200, "Expected value length to be equal")
+
+		encoded, err := EncodeBerTLV(tlvs)
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, data, encoded, "Expected encoded data to be equal")
+	})
+
+	t.Run("Truncated value", func(t *testing.T) {
+		_, err := DecodeBerTLV([]byte{0x9F, 0x26, 0x08, 0x11})
+		assert.ErrorIs(t, err, ErrNotEnoughData)
+	})
+}
+
+func TestEncodeBerTLV(t *testing.T) {
+	tlvs, err := DecodeBerTLV(iccData)
+	require.Nil(t, err, "Error should be nil")
+
+	encoded, err := EncodeBerTLV(tlvs)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, iccData, encoded, "Expected encoded data to be equal")
+
+	for _, tag := range []string{"XYZ", "", "9F", "9F81", "5F2A01", "9A01"} {
+		_, err = EncodeBerTLV([]TLV{{Tag: tag}})
+		assert.NotNil(t, err, "Expected error invalid tag %q", tag)
+	}
+
+	for _, tag := range []string{"9A", "9F26", "5F2A", "DF8101"} {
+		_, err = EncodeBerTLV([]TLV{{Tag: tag}})
+		assert.Nil(t, err, "Expected tag %q to be valid", tag)
+	}
+}
+
+func TestFieldTags(t *testing.T) {
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.AddMTI("0200")
+	require.Nil(t, isoParser.SetField(55, string(iccData)))
+
+	value, err := isoParser.GetTag(55, "9f36")
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, []byte{0x00, 0x01}, value, "Expected tag value to be equal")
+
+	require.Nil(t, isoParser.SetTag(55, "5F2A", []byte{0x08, 0x40}))
+	require.Nil(t, isoParser.SetTag(55, "9A", []byte{0x25, 0x10, 0x16}))
+
+	tlvs, err := isoParser.GetTags(55)
+	require.Nil(t, err, "Error should be nil")
+	tags := make([]string, len(tlvs))
+	for i, tlv := range tlvs {
+		tags[i] = tlv.Tag
+	}
+	assert.Equal(t, []string{"9F26", "5F2A", "70", "9A"}, tags, "Expected tag order to be kept")
+
+	isoMsg, err := isoParser.Marshal()
+	require.Nil(t, err, "Error should be nil")
+
+	unpacked, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, unpacked.Unmarshal(isoMsg))
+
+	currency, err := unpacked.GetTag(55, "5F2A")
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, []byte{0x08, 0x40}, currency, "Expected tag value to be equal")
+
+	_, err = unpacked.GetTag(55, "9F27")
+	assert.NotNil(t, err, "Expected error tag not exist")
+
+	err = unpacked.SetTag(3, "9F27", []byte{0x80})
+	assert.ErrorIs(t, err, ErrNotTLVField)
+}
+
+func TestFieldTagsNested(t *testing.T) {
+	isoParser, err := NewFromSpec(SpecData1987)
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.AddMTI("0200")
+	require.Nil(t, isoParser.SetField(55, "\x70\x03\x9F\x26\x00"))
+
+	// A tag inside a template is replaced in place instead of added at the top level
+	require.Nil(t, isoParser.SetTag(55, "9F26", []byte{0x09}))
+	bit55, _ := isoParser.GetField(55)
+	assert.Equal(t, "\x70\x04\x9F\x26\x01\x09", bit55, "Expected nested tag to be replaced")
+
+	value, err := isoParser.GetTag(55, "9F26")
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, []byte{0x09}, value, "Expected tag value to be equal")
+}
+
+func specWithPrivateTLV() SpecData {
+	return SpecData{
+		Fields: map[int]FieldSpec{
+			3:  {ContentType: "n", LenType: "fixed", MaxLen: 6},
+			48: {ContentType: "ans", LenType: "lllvar", MaxLen: 999, Format: FormatTLV, TLV: &TLVSpec{TagLen: 2, LenDigits: 3}},
+			62: {ContentType: "ans", LenType: "lllvar", MaxLen: 999, Format: FormatTLV, TLV: &TLVSpec{TagLen: 2, LenDigits: 2, Order: TLVOrderLengthFirst}},
+		},
+	}
+}
+
+func TestDecodeTLV(t *testing.T) {
+	t.Run("Tag first", func(t *testing.T) {
+		tlvs, err := decodeTLV(&TLVSpec{TagLen: 2, LenDigits: 3}, []byte("01005HELLO02003ABC"))
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, []TLV{{Tag: "01", Value: []byte("HELLO")}, {Tag: "02", Value: []byte("ABC")}}, tlvs)
+	})
+
+	t.Run("Length first including tag", func(t *testing.T) {
+		spec := &TLVSpec{TagLen: 2, LenDigits: 2, Order: TLVOrderLengthFirst, LenIncludesTag: true}
+		tlvs, err := decodeTLV(spec, []byte("07AAHELLO"))
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, []TLV{{Tag: "AA", Value: []byte("HELLO")}}, tlvs)
+
+		encoded, err := encodeTLV(spec, tlvs)
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, []byte("07AAHELLO"), encoded, "Expected encoded data to be equal")
+	})
+
+	t.Run("Binary length", func(t *testing.T) {
+		spec := &TLVSpec{TagLen: 1, LenDigits: 4, LenEncoding: "binary"}
+		encoded, err := encodeTLV(spec, []TLV{{Tag: "X", Value: []byte("AB")}})
+		require.Nil(t, err, "Error should be nil")
+		assert.Equal(t, []byte{'X', 0x00, 0x02, 'A', 'B'}, encoded, "Expected encoded data to be equal")
+	})
+
+	t.Run("Truncated value", func(t *testing.T) {
+		_, err := decodeTLV(&TLVSpec{TagLen: 2, LenDigits: 3}, []byte("01009HELLO"))
+		assert.ErrorIs(t, err, ErrNotEnoughData)
+	})
+
+	t.Run("Invalid tag width", func(t *testing.T) {
+		_, err := encodeTLV(&TLVSpec{TagLen: 2, LenDigits: 3}, []TLV{{Tag: "001", Value: []byte("A")}})
+		assert.NotNil(t, err, "Expected error invalid tag")
+	})
+}
+
+func TestFieldPrivateTags(t *testing.T) {
+	isoParser, err := NewFromSpec(specWithPrivateTLV())
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser.AddMTI("0200")
+	isoParser.SetField(3, "000000")
+	require.Nil(t, isoParser.SetTag(48, "01", []byte("HELLO")))
+	require.Nil(t, isoParser.SetTag(48, "02", []byte("ABC")))
+	require.Nil(t, isoParser.SetTag(62, "AA", []byte("XY")))
+
+	isoMsg, err := isoParser.MarshalString()
+	require.Nil(t, err, "Error should be nil")
+	require.Equal(t, "0200"+"2000000000010004"+"000000"+"018"+"01005HELLO02003ABC"+"006"+"02AAXY", isoMsg, "Expected iso message to be equal")
+
+	unpacked, err := NewFromSpec(specWithPrivateTLV())
+	require.Nil(t, err, "Error should be nil")
+	require.Nil(t, unpacked.UnmarshalString(isoMsg))
+
+	tags, err := unpacked.GetTagMap(48)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, map[string]string{"01": "HELLO", "02": "ABC"}, tags, "Expected tags to be equal")
+
+	value, err := unpacked.GetTag(62, "AA")
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, []byte("XY"), value, "Expected tag value to be equal")
+
+	_, err = unpacked.GetTag(62, "aa")
+	assert.NotNil(t, err, "Expected error tag not exist")
+}
+
+func TestTLVSpecValidation(t *testing.T) {
+	spec := specWithPrivateTLV()
+	spec.Fields[48] = FieldSpec{ContentType: "ans", LenType: "lllvar", MaxLen: 999, Format: FormatTLV}
+
+	_, err := NewFromSpec(spec)
+	assert.NotNil(t, err, "Expected error missing TLV spesification")
+
+	spec.Fields[48] = FieldSpec{ContentType: "ans", LenType: "lllvar", MaxLen: 999, Format: FormatTLV, TLV: &TLVSpec{TagLen: 2, LenDigits: 3, Order: "vlt"}}
+	_, err = NewFromSpec(spec)
+	assert.NotNil(t, err, "Expected error invalid TLV order")
+}
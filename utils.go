@@ -17,7 +17,7 @@ func extractMti(str string) (mti MtiData, err error) {
 }
 
 // Get variable length form field type in field spesification
-// the field type is: llvar, lllvar and llllvar
+// the field type is: llvar, lllvar, llllvar and lllllvar
 func getVariableLengthFromString(str string) (num int, err error) {
 	str = strings.ToLower(str)
 	if str == "llvar" {
@@ -29,8 +29,11 @@ func getVariableLengthFromString(str string) (num int, err error) {
 	if str == "llllvar" {
 		return 4, nil
 	}
+	if str == "lllllvar" {
+		return 5, nil
+	}
 
-	return num, fmt.Errorf("%s is an invalid LenType", str)
+	return num, fmt.Errorf("%s is an %w", str, ErrInvalidLenType)
 }
 
 // Create text with prefix padding if the text length is less than the maximum length
@@ -198,6 +201,11 @@ func BitsIntArrayToHex(bits []int) (string, error) {
 }
 
 func GetSortedKeyFields(source map[int]string) []int {
+	return sortedKeys(source)
+}
+
+// Get the keys of a map by field or subfield number in ascending order
+func sortedKeys[V any](source map[int]V) []int {
 	ks := make([]int, len(source))
 
 	index := 0
@@ -32,9 +32,15 @@ func TestGetVariableLengthFromString(t *testing.T) {
 		assert.Equal(t, 2, num, "Expected num to be equal")
 	})
 
+	t.Run("Long variable length", func(t *testing.T) {
+		num, err := getVariableLengthFromString("LLLLLVAR")
+		assert.Nil(t, err, "Expected nil value error")
+		assert.Equal(t, 5, num, "Expected num to be equal")
+	})
+
 	t.Run("Invalid code length", func(t *testing.T) {
 		_, err := getVariableLengthFromString("dsdsfe")
-		assert.NotNil(t, err, "Expected error length ")
+		assert.ErrorIs(t, err, ErrInvalidLenType, "Expected error length ")
 	})
 }
 
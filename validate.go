@@ -0,0 +1,50 @@
+package iso8583parser
+
+// Validate checks the MTI and every field that has been set against the specification
+// and returns all violations at once as ValidationErrors:
+// invalid MTI, unknown field, length outside MinLen and MaxLen, invalid content type and missing mandatory field.
+// Returns nil if the message is valid.
+func (iso *Iso8583Data) Validate() error {
+	var errs ValidationErrors
+
+	if err := iso.Mti.validate(); err != nil {
+		errs = append(errs, newFieldError(0, PhaseMTI, -1, err))
+	}
+
+	elements := iso.Elements.getElements()
+	for _, field := range GetSortedKeyFields(elements) {
+		spec, ok := iso.Spec.Fields[field]
+		if !ok {
+			errs = append(errs, newFieldError(field, PhaseValue, -1, ErrNoFieldSpec))
+			continue
+		}
+
+		data, _ := iso.Elements.getElement(field)
+		if err := spec.checkLength(field, len(data)); err != nil {
+			errs = append(errs, newFieldError(field, PhaseValue, -1, err))
+		}
+
+		if err := validateContent(field, spec, data); err != nil {
+			errs = append(errs, newFieldError(field, PhaseValue, -1, err))
+		}
+	}
+
+	errs = append(errs, iso.validateMandatory()...)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Check that the mandatory fields for the MTI in specification are present
+func (iso *Iso8583Data) validateMandatory() []error {
+	var errs []error
+	for _, field := range iso.Spec.MandatoryFields[iso.Mti.Get()] {
+		if _, exist := iso.Elements.getElement(field); !exist {
+			errs = append(errs, newFieldError(field, PhaseValue, -1, ErrMissingMandatoryField))
+		}
+	}
+
+	return errs
+}
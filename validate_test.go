@@ -0,0 +1,120 @@
+package iso8583parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func specWithMandatoryFields() SpecData {
+	return SpecData{
+		Fields: map[int]FieldSpec{
+			2:  {ContentType: "n", LenType: "llvar", MaxLen: 19, MinLen: 12},
+			3:  {ContentType: "n", LenType: "fixed", MaxLen: 6},
+			4:  {ContentType: "n", LenType: "fixed", MaxLen: 12},
+			11: {ContentType: "n", LenType: "fixed", MaxLen: 6},
+		},
+		MandatoryFields: map[string][]int{
+			"0200": {3, 4, 11},
+		},
+	}
+}
+
+func fieldErrorsOf(t *testing.T, err error) []FieldError {
+	var validationErrs ValidationErrors
+	require.True(t, errors.As(err, &validationErrs), "Expected validation errors, got %v", err)
+
+	fieldErrs := make([]FieldError, 0, len(validationErrs))
+	for _, e := range validationErrs {
+		var fieldErr *FieldError
+		require.True(t, errors.As(e, &fieldErr), "Expected field error, got %v", e)
+		fieldErrs = append(fieldErrs, *fieldErr)
+	}
+
+	return fieldErrs
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		isoParser, err := NewFromSpec(specWithMandatoryFields())
+		require.Nil(t, err, "Error should be nil")
+
+		isoParser.AddMTI("0200")
+		isoParser.SetField(3, "000000")
+		isoParser.SetField(4, "1500")
+		isoParser.SetField(11, "123456")
+
+		assert.Nil(t, isoParser.Validate(), "Error should be nil")
+	})
+
+	t.Run("All violations", func(t *testing.T) {
+		isoParser, err := NewFromSpec(specWithMandatoryFields())
+		require.Nil(t, err, "Error should be nil")
+
+		isoParser.AddMTI("0200")
+		isoParser.Elements.setElement(2, "4111")
+		isoParser.Elements.setElement(3, "00A000")
+		isoParser.Elements.setElement(48, "unknown")
+
+		err = isoParser.Validate()
+		fieldErrs := fieldErrorsOf(t, err)
+		require.Len(t, fieldErrs, 5, "Expected five violations")
+
+		assert.Equal(t, 2, fieldErrs[0].Field)
+		assert.ErrorIs(t, fieldErrs[0].Err, ErrFieldTooShort)
+		assert.Equal(t, 3, fieldErrs[1].Field)
+		assert.ErrorIs(t, fieldErrs[1].Err, ErrInvalidContentType)
+		assert.Equal(t, 48, fieldErrs[2].Field)
+		assert.ErrorIs(t, fieldErrs[2].Err, ErrNoFieldSpec)
+		assert.Equal(t, 4, fieldErrs[3].Field)
+		assert.ErrorIs(t, fieldErrs[3].Err, ErrMissingMandatoryField)
+		assert.Equal(t, 11, fieldErrs[4].Field)
+		assert.ErrorIs(t, fieldErrs[4].Err, ErrMissingMandatoryField)
+
+		assert.ErrorIs(t, err, ErrMissingMandatoryField)
+	})
+}
+
+func TestUnmarshalCollectAllErrors(t *testing.T) {
+	isoMsg := "0200" + "6000000000000000" + "044111" + "00A000"
+
+	t.Run("Stop at first error", func(t *testing.T) {
+		isoParser, err := NewFromSpec(specWithMandatoryFields())
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.UnmarshalString(isoMsg)
+		assert.ErrorIs(t, err, ErrFieldTooShort)
+		assert.NotErrorIs(t, err, ErrInvalidContentType)
+	})
+
+	t.Run("Collect all errors", func(t *testing.T) {
+		isoParser, err := NewFromSpec(specWithMandatoryFields(), WithCollectAllErrors())
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.UnmarshalString(isoMsg)
+		fieldErrs := fieldErrorsOf(t, err)
+		require.Len(t, fieldErrs, 4, "Expected four violations")
+
+		assert.Equal(t, FieldError{Field: 2, Phase: PhaseValue, Offset: 22, Expected: 12, Actual: 4, Err: fieldErrs[0].Err}, fieldErrs[0])
+		assert.Equal(t, 3, fieldErrs[1].Field)
+		assert.Equal(t, 26, fieldErrs[1].Offset)
+		assert.ErrorIs(t, fieldErrs[1].Err, ErrInvalidContentType)
+		assert.Equal(t, 4, fieldErrs[2].Field)
+		assert.Equal(t, 11, fieldErrs[3].Field)
+
+		bit3, _ := isoParser.GetField(3)
+		assert.Equal(t, "00A000", bit3, "Expected invalid value to be kept")
+	})
+
+	t.Run("Unreadable message", func(t *testing.T) {
+		isoParser, err := NewFromSpec(specWithMandatoryFields(), WithCollectAllErrors())
+		require.Nil(t, err, "Error should be nil")
+
+		err = isoParser.UnmarshalString("0200" + "6000000000000000" + "044111" + "00")
+		fieldErrs := fieldErrorsOf(t, err)
+		require.Len(t, fieldErrs, 2, "Expected two errors")
+		assert.ErrorIs(t, fieldErrs[1].Err, ErrValueTooShort)
+	})
+}
@@ -0,0 +1,74 @@
+package iso8583parser
+
+import (
+	"fmt"
+	"sort"
+)
+
+// VersionSpecs maps the MTI version digit to the built-in specification of that ISO 8583 version
+var VersionSpecs = map[int]SpecData{
+	0: SpecData1987,
+	1: SpecData1993,
+	2: SpecData2003,
+}
+
+// Create a new Iso8583Data object that picks its data specification by the version digit of the MTI,
+// the specification is selected whenever AddMTI or Unmarshal is called, use VersionSpecs for the built-in specifications.
+// Until a MTI is known the specification of the lowest version is used.
+// An error may occur if one of the specifications is invalid
+// or if the specifications do not share the same MTI encoding and header
+func NewFromVersionSpecs(specs map[int]SpecData, opts ...Option) (iso *Iso8583Data, err error) {
+	if len(specs) == 0 {
+		return iso, ErrEmptySpec
+	}
+
+	versions := make([]int, 0, len(specs))
+	for version := range specs {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	mtiEncoding := specs[versions[0]].Fields[0].Encoding
+	for _, version := range versions {
+		if version < 0 || version > 9 {
+			return iso, fmt.Errorf("%w: %d", ErrUnsupportedMtiVersion, version)
+		}
+
+		if specs[version].Fields[0].Encoding != mtiEncoding {
+			return iso, fmt.Errorf("version %d: MTI encoding must be equal in all specifications", version)
+		}
+
+		if !sameHeaderSpec(specs[version].Header, specs[versions[0]].Header) {
+			return iso, fmt.Errorf("version %d: Header must be equal in all specifications", version)
+		}
+
+		if err := checkSpec(specs[version]); err != nil {
+			return iso, fmt.Errorf("version %d: %w", version, err)
+		}
+	}
+
+	iso, err = createIsoObject(specs[versions[0]], opts...)
+	if err != nil {
+		return iso, err
+	}
+
+	iso.versionSpecs = specs
+	return iso, nil
+}
+
+// Switch to the data specification of the MTI version,
+// nothing changes when the object was not created with NewFromVersionSpecs
+func (iso *Iso8583Data) useVersionSpec(mti string) error {
+	if iso.versionSpecs == nil || len(mti) == 0 {
+		return nil
+	}
+
+	version := int(mti[0] - '0')
+	spec, ok := iso.versionSpecs[version]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnsupportedMtiVersion, version)
+	}
+
+	iso.Spec = spec
+	return nil
+}
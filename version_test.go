@@ -0,0 +1,69 @@
+package iso8583parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionSpecFiles(t *testing.T) {
+	files := map[string]SpecData{
+		"spec1987.yml": SpecData1987,
+		"spec1993.yml": SpecData1993,
+		"spec2003.yml": SpecData2003,
+	}
+
+	for filename, expected := range files {
+		t.Run(filename, func(t *testing.T) {
+			specData, err := SpecFromFile(filename)
+			require.Nil(t, err, "Error should be nil")
+			assert.Equal(t, expected, specData, "Expected yaml and Go specification to be equal")
+		})
+	}
+}
+
+func TestUnmarshalVersionSpecs(t *testing.T) {
+	builder, err := NewFromSpec(SpecData2003)
+	require.Nil(t, err, "Error should be nil")
+
+	builder.AddMTI("2100")
+	builder.SetField(11, "000000123456")
+	builder.SetField(48, "PRIVATE")
+	isoMsg, err := builder.Marshal()
+	require.Nil(t, err, "Error should be nil")
+
+	isoParser, err := NewFromVersionSpecs(VersionSpecs)
+	require.Nil(t, err, "Error should be nil")
+	assert.Equal(t, SpecData1987, isoParser.Spec, "Expected lowest version to be used")
+
+	require.Nil(t, isoParser.Unmarshal(isoMsg))
+	assert.Equal(t, SpecData2003, isoParser.Spec, "Expected 2003 specification to be selected")
+
+	bit11, _ := isoParser.GetField(11)
+	assert.Equal(t, "000000123456", bit11, "Expected Bit11 to be equal")
+	bit48, _ := isoParser.GetField(48)
+	assert.Equal(t, "PRIVATE", bit48, "Expected Bit48 to be equal")
+
+	isoParser.Reset()
+	require.Nil(t, isoParser.UnmarshalString("0800"+"0020000000000000"+"123456"))
+	assert.Equal(t, SpecData1987, isoParser.Spec, "Expected 1987 specification to be selected")
+
+	require.Nil(t, isoParser.AddMTI("1200"))
+	assert.Equal(t, SpecData1993, isoParser.Spec, "Expected 1993 specification to be selected")
+}
+
+func TestUnsupportedMtiVersion(t *testing.T) {
+	isoParser, err := NewFromVersionSpecs(map[int]SpecData{0: SpecData1987, 1: SpecData1993})
+	require.Nil(t, err, "Error should be nil")
+
+	err = isoParser.AddMTI("2100")
+	assert.ErrorIs(t, err, ErrUnsupportedMtiVersion)
+
+	err = isoParser.UnmarshalString("2100" + "0020000000000000" + "000000123456")
+	var fieldErr *FieldError
+	require.True(t, errors.As(err, &fieldErr), "Expected field error")
+	assert.Equal(t, PhaseMTI, fieldErr.Phase, "Expected phase to be equal")
+	assert.ErrorIs(t, err, ErrUnsupportedMtiVersion)
+}